@@ -1,13 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/peterh/liner"
@@ -20,7 +28,7 @@ func main() {
 	flag.Parse()
 
 	fmt.Printf("Koorde Web Cache interactive client. Connected to %s\n", *addr)
-	fmt.Println("Available commands: cache/metrics/health/hotspots/debug/clear/help/exit")
+	fmt.Println("Available commands: cache/metrics/health/hotspots/debug/stats/bench/watch/follow/help/exit")
 	fmt.Println("")
 
 	// Setup liner shell
@@ -97,7 +105,7 @@ func main() {
 			}
 			fmt.Printf("Content (%d bytes):\n%s\n", len(body), contentPreview)
 
-		case "metrics", "stats":
+		case "metrics":
 			resp, err := client.Get(fmt.Sprintf("%s/metrics", currentAddr))
 			if err != nil {
 				fmt.Printf("Metrics request failed: %v\n", err)
@@ -116,17 +124,35 @@ func main() {
 			fmt.Println(string(prettyJSON))
 
 		case "health":
-			resp, err := client.Get(fmt.Sprintf("%s/health", currentAddr))
-			if err != nil {
-				fmt.Printf("Health check failed: %v\n", err)
-				continue
+			var retry time.Duration
+			for i := 1; i+1 < len(args); i++ {
+				if args[i] == "-retry" {
+					if d, err := time.ParseDuration(args[i+1]); err == nil {
+						retry = d
+					}
+				}
 			}
-			defer resp.Body.Close()
 
 			var health map[string]interface{}
-			if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
-				fmt.Printf("Failed to parse health response: %v\n", err)
-				continue
+			if retry > 0 {
+				fmt.Printf("Waiting up to %s for %s to become healthy...\n", retry, currentAddr)
+				h, err := waitForHealthy(client, currentAddr, retry)
+				if err != nil {
+					fmt.Printf("✗ %v\n", err)
+					os.Exit(1)
+				}
+				health = h
+			} else {
+				resp, err := client.Get(fmt.Sprintf("%s/health", currentAddr))
+				if err != nil {
+					fmt.Printf("Health check failed: %v\n", err)
+					continue
+				}
+				defer resp.Body.Close()
+				if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+					fmt.Printf("Failed to parse health response: %v\n", err)
+					continue
+				}
 			}
 
 			healthy := health["healthy"].(bool)
@@ -156,12 +182,16 @@ func main() {
 			hotspots := metrics["hotspots"].(map[string]interface{})
 			count := int(hotspots["count"].(float64))
 			urls := hotspots["urls"].([]interface{})
+			replicas, _ := hotspots["replicas"].(map[string]interface{})
 
-			fmt.Printf("Hotspots detected: %d\n", count)
+			fmt.Printf("Hotspots detected: %d (replication factor: %v)\n", count, hotspots["replication_factor"])
 			if count > 0 {
 				fmt.Println("Hot URLs:")
 				for i, url := range urls {
 					fmt.Printf("  [%d] %s\n", i+1, url)
+					if addrs, ok := replicas[url.(string)].([]interface{}); ok && len(addrs) > 0 {
+						fmt.Printf("      replicas: %v\n", addrs)
+					}
 				}
 			} else {
 				fmt.Println("  (none)")
@@ -184,6 +214,91 @@ func main() {
 			prettyJSON, _ := json.MarshalIndent(debug, "", "  ")
 			fmt.Println(string(prettyJSON))
 
+		case "stats":
+			resp, err := client.Get(fmt.Sprintf("%s/metrics/statsd", currentAddr))
+			if err != nil {
+				fmt.Printf("Statsd sample request failed: %v\n", err)
+				continue
+			}
+			defer resp.Body.Close()
+
+			var sampleResp struct {
+				Enabled bool               `json:"enabled"`
+				Sample  map[string]float64 `json:"sample"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&sampleResp); err != nil {
+				fmt.Printf("Failed to parse statsd sample: %v\n", err)
+				continue
+			}
+
+			if !sampleResp.Enabled {
+				fmt.Println("statsd emitter not configured on this node (see --statsd)")
+				continue
+			}
+			if len(sampleResp.Sample) == 0 {
+				fmt.Println("(no statsd sample flushed yet)")
+				continue
+			}
+			names := make([]string, 0, len(sampleResp.Sample))
+			for name := range sampleResp.Sample {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Println("Last flushed statsd sample:")
+			for _, name := range names {
+				fmt.Printf("  %-40s %g\n", name, sampleResp.Sample[name])
+			}
+
+		case "bench":
+			if len(args) < 2 {
+				fmt.Println("Usage: bench <url> [-n count] [-c concurrency] [-d duration]")
+				fmt.Println("Example: bench https://www.example.com -n 500 -c 20")
+				continue
+			}
+			benchURL := args[1]
+			count := 100
+			concurrency := 10
+			var duration time.Duration
+			for i := 2; i+1 < len(args); i++ {
+				switch args[i] {
+				case "-n":
+					if v, err := strconv.Atoi(args[i+1]); err == nil {
+						count = v
+					}
+				case "-c":
+					if v, err := strconv.Atoi(args[i+1]); err == nil {
+						concurrency = v
+					}
+				case "-d":
+					if d, err := time.ParseDuration(args[i+1]); err == nil {
+						duration = d
+					}
+				}
+			}
+
+			benchCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			runBench(benchCtx, client, currentAddr, benchURL, count, concurrency, duration)
+			cancel()
+
+		case "watch":
+			interval := 2 * time.Second
+			if len(args) >= 2 {
+				if d, err := time.ParseDuration(args[1]); err == nil {
+					interval = d
+				} else if n, err := strconv.Atoi(args[1]); err == nil {
+					interval = time.Duration(n) * time.Second
+				}
+			}
+
+			watchCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			runWatch(watchCtx, client, currentAddr, interval)
+			cancel()
+
+		case "follow":
+			followCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			runFollow(followCtx, currentAddr)
+			cancel()
+
 		case "use", "connect":
 			if len(args) < 2 {
 				fmt.Println("Usage: use <addr>")
@@ -215,6 +330,11 @@ func main() {
 			fmt.Println("  health            - Check node health")
 			fmt.Println("  hotspots          - Show detected hot URLs")
 			fmt.Println("  debug             - Show routing table info")
+			fmt.Println("  stats             - Show last flushed statsd sample")
+			fmt.Println("  bench <url>       - Load-test <url> via /cache (Ctrl+C to stop early)")
+			fmt.Println("                      [-n count] [-c concurrency] [-d duration]")
+			fmt.Println("  watch <interval>  - Poll /metrics on interval, diffing hotspots/counters")
+			fmt.Println("  follow            - Stream /hotspots/stream, printing hot/cool transitions live")
 			fmt.Println("  use <addr>        - Switch to different node")
 			fmt.Println("  help              - Show this help")
 			fmt.Println("  exit              - Exit client")
@@ -223,6 +343,10 @@ func main() {
 			fmt.Println("  cache https://www.example.com")
 			fmt.Println("  cache https://httpbin.org/json")
 			fmt.Println("  metrics")
+			fmt.Println("  health -retry 30s")
+			fmt.Println("  bench https://www.example.com -n 500 -c 20")
+			fmt.Println("  watch 2s")
+			fmt.Println("  follow")
 			fmt.Println("  use http://localhost:8081")
 
 		case "exit", "quit", "q":
@@ -236,3 +360,339 @@ func main() {
 	}
 }
 
+// waitForHealthy polls addr's /health endpoint once a second until it
+// reports healthy or timeout elapses, for `health -retry` to let CI
+// scripts block until a node comes up.
+func waitForHealthy(client *http.Client, addr string, timeout time.Duration) (map[string]interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+
+	for {
+		resp, err := client.Get(fmt.Sprintf("%s/health", addr))
+		if err != nil {
+			lastErr = err
+		} else {
+			var health map[string]interface{}
+			decErr := json.NewDecoder(resp.Body).Decode(&health)
+			resp.Body.Close()
+			if decErr != nil {
+				lastErr = decErr
+			} else if healthy, ok := health["healthy"].(bool); ok && healthy {
+				return health, nil
+			} else {
+				lastErr = fmt.Errorf("reported unhealthy")
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("node at %s did not become healthy within %s: %w", addr, timeout, lastErr)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// benchResult is one completed /cache request, as runBench's workers
+// report it back for aggregation.
+type benchResult struct {
+	err      error
+	latency  time.Duration
+	cacheHdr string
+	respNode string
+}
+
+// runBench closed-loop load-tests url against addr's /cache endpoint:
+// concurrency workers each issue requests back-to-back (no think time)
+// until either count requests have been issued or duration has elapsed
+// (duration, if set, takes precedence over count), printing a live
+// progress bar and, on completion or Ctrl+C (via ctx), a summary of
+// p50/p95/p99 latency, hit rate (from X-Cache), and which responsible
+// nodes served the load (from X-Responsible-Node).
+func runBench(ctx context.Context, client *http.Client, addr, url string, count, concurrency int, duration time.Duration) {
+	fmt.Printf("Benchmarking %s via %s/cache (concurrency=%d)\n", url, addr, concurrency)
+
+	results := make(chan benchResult, concurrency*2)
+	var issued int64
+	var completed int64
+	deadline := time.Time{}
+	if duration > 0 {
+		deadline = time.Now().Add(duration)
+	}
+
+	worker := func() {
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			if duration > 0 {
+				if time.Now().After(deadline) {
+					return
+				}
+			} else if atomic.AddInt64(&issued, 1) > int64(count) {
+				return
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/cache?url=%s", addr, url), nil)
+			if err != nil {
+				results <- benchResult{err: err}
+				continue
+			}
+
+			start := time.Now()
+			resp, err := client.Do(req)
+			res := benchResult{err: err, latency: time.Since(start)}
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				res.cacheHdr = resp.Header.Get("X-Cache")
+				res.respNode = resp.Header.Get("X-Responsible-Node")
+			}
+			atomic.AddInt64(&completed, 1)
+
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var latencies []time.Duration
+	var hits, errs int
+	nodeCounts := make(map[string]int)
+
+	progress := time.NewTicker(200 * time.Millisecond)
+	defer progress.Stop()
+
+resultsLoop:
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				break resultsLoop
+			}
+			if res.err != nil {
+				errs++
+				continue
+			}
+			latencies = append(latencies, res.latency)
+			if strings.HasPrefix(res.cacheHdr, "HIT") {
+				hits++
+			}
+			if res.respNode != "" {
+				nodeCounts[res.respNode]++
+			}
+		case <-progress.C:
+			fmt.Printf("\r%s", benchProgressBar(atomic.LoadInt64(&completed), count, duration, deadline))
+		case <-ctx.Done():
+			break resultsLoop
+		}
+	}
+	fmt.Println()
+
+	total := len(latencies) + errs
+	if total == 0 {
+		fmt.Println("No requests completed.")
+		return
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	pct := func(p float64) time.Duration {
+		if len(latencies) == 0 {
+			return 0
+		}
+		idx := int(p * float64(len(latencies)-1))
+		return latencies[idx]
+	}
+
+	fmt.Printf("Completed %d requests (%d errors)\n", total, errs)
+	if len(latencies) > 0 {
+		fmt.Printf("Latency: p50=%s p95=%s p99=%s\n", pct(0.50), pct(0.95), pct(0.99))
+		fmt.Printf("Hit rate: %.1f%% (%d/%d)\n", 100*float64(hits)/float64(len(latencies)), hits, len(latencies))
+	}
+	if len(nodeCounts) > 0 {
+		fmt.Println("Served by:")
+		nodes := make([]string, 0, len(nodeCounts))
+		for n := range nodeCounts {
+			nodes = append(nodes, n)
+		}
+		sort.Strings(nodes)
+		for _, n := range nodes {
+			fmt.Printf("  %-25s %d\n", n, nodeCounts[n])
+		}
+	}
+}
+
+// benchProgressBar renders a `[####----] 42%`-style bar from whichever of
+// count/duration is bounding this bench run.
+func benchProgressBar(completed int64, count int, duration time.Duration, deadline time.Time) string {
+	var frac float64
+	switch {
+	case duration > 0:
+		remaining := time.Until(deadline)
+		elapsed := duration - remaining
+		frac = elapsed.Seconds() / duration.Seconds()
+	case count > 0:
+		frac = float64(completed) / float64(count)
+	}
+	if frac < 0 {
+		frac = 0
+	}
+	if frac > 1 {
+		frac = 1
+	}
+
+	const width = 30
+	filled := int(frac * width)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", width-filled)
+	return fmt.Sprintf("[%s] %3.0f%% (%d completed)", bar, frac*100, completed)
+}
+
+// runWatch polls addr's /metrics endpoint every interval, printing a
+// one-line summary each time plus any hot URL that newly appeared or
+// cooled since the previous poll, until ctx is cancelled (Ctrl+C).
+func runWatch(ctx context.Context, client *http.Client, addr string, interval time.Duration) {
+	fmt.Printf("Watching %s every %s (Ctrl+C to stop)\n", addr, interval)
+
+	var prevHot map[string]bool
+	var prevHits, prevMisses int64
+	first := true
+
+	poll := func() {
+		resp, err := client.Get(fmt.Sprintf("%s/metrics", addr))
+		if err != nil {
+			fmt.Printf("metrics request failed: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		var m map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+			fmt.Printf("failed to parse metrics: %v\n", err)
+			return
+		}
+
+		cacheStats, _ := m["cache"].(map[string]interface{})
+		hits := int64(cacheStats["hits"].(float64))
+		misses := int64(cacheStats["misses"].(float64))
+		hitRate, _ := cacheStats["hit_rate"].(float64)
+
+		hotspots, _ := m["hotspots"].(map[string]interface{})
+		urls, _ := hotspots["urls"].([]interface{})
+		curHot := make(map[string]bool, len(urls))
+		for _, u := range urls {
+			if s, ok := u.(string); ok {
+				curHot[s] = true
+			}
+		}
+
+		fmt.Printf("[%s] hits=%d(+%d) misses=%d(+%d) hit_rate=%.1f%% hot=%d\n",
+			time.Now().Format("15:04:05"), hits, hits-prevHits, misses, misses-prevMisses, hitRate*100, len(curHot))
+
+		if !first {
+			for u := range curHot {
+				if !prevHot[u] {
+					fmt.Printf("  + new hot URL: %s\n", u)
+				}
+			}
+			for u := range prevHot {
+				if !curHot[u] {
+					fmt.Printf("  - cooled: %s\n", u)
+				}
+			}
+		}
+
+		prevHot, prevHits, prevMisses, first = curHot, hits, misses, false
+	}
+
+	poll()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("Stopped watching")
+			return
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// hotspotStreamEvent mirrors the JSON server.handleHotspotStream writes
+// on GET /hotspots/stream, for runFollow to decode.
+type hotspotStreamEvent struct {
+	Type          string    `json:"type"`
+	URL           string    `json:"url"`
+	Average       float64   `json:"average"`
+	TotalRequests float64   `json:"total_requests"`
+	NodeID        string    `json:"node_id"`
+	Timestamp     time.Time `json:"ts"`
+}
+
+// runFollow connects to GET /hotspots/stream and prints a scrolling log
+// of hot/cool transitions as they arrive, until ctx is cancelled (Ctrl+C)
+// or the connection drops. Uses its own client with no request timeout,
+// since the shared REPL client's timeout is sized for one-shot requests
+// and would otherwise cut the stream off mid-flight.
+func runFollow(ctx context.Context, addr string) {
+	fmt.Printf("Following hotspot transitions on %s (Ctrl+C to stop)\n", addr)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/hotspots/stream", addr), nil)
+	if err != nil {
+		fmt.Printf("failed to build request: %v\n", err)
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	streamClient := &http.Client{}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("Stopped following")
+			return
+		}
+		fmt.Printf("stream request failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data := strings.TrimPrefix(line, "data: ")
+		if data == line {
+			continue // heartbeat comment or blank line, not a data frame
+		}
+
+		var e hotspotStreamEvent
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			continue
+		}
+
+		label := strings.ToUpper(e.Type)
+		fmt.Printf("[%s] %-4s %s avg=%.1f total=%.0f node=%s\n",
+			e.Timestamp.Format("15:04:05"), label, e.URL, e.Average, e.TotalRequests, e.NodeID)
+	}
+
+	if ctx.Err() != nil {
+		fmt.Println("Stopped following")
+	} else if err := scanner.Err(); err != nil {
+		fmt.Printf("stream ended: %v\n", err)
+	} else {
+		fmt.Println("Stream closed by server")
+	}
+}
+
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -43,16 +45,147 @@ func parseTargetList(raw string, fallback string) []string {
 	return targets
 }
 
+// traceEntry is one replayed request: key is the cache key to request
+// (preserved verbatim from the trace so hit/miss behavior reflects real
+// reuse, not a synthetic Zipf draw), and delay is the time since the
+// previous entry in the trace, used to reproduce the trace's original
+// inter-arrival timing (scaled by --speedup).
+type traceEntry struct {
+	delay time.Duration
+	key   string
+	size  int64
+}
+
+// parseTrace loads a request trace in one of two supported formats,
+// auto-detected from the first non-empty line:
+//
+//   - the classic Wikipedia CDN trace format: whitespace-separated
+//     "timestamp url size" (timestamp in seconds, fractional allowed)
+//   - a generic CSV: "timestamp_ms,key,size_bytes", with an optional
+//     non-numeric header row
+//
+// Entries must already be in non-decreasing timestamp order (the
+// convention both source formats follow); parseTrace turns absolute
+// timestamps into relative delays between consecutive entries.
+func parseTrace(path string) ([]traceEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening trace file: %w", err)
+	}
+	defer f.Close()
+
+	var csvFormat bool
+	var detected bool
+
+	var entries []traceEntry
+	var prevTs float64
+	haveSeen := false
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if !detected {
+			csvFormat = strings.Contains(line, ",")
+			detected = true
+		}
+
+		var tsRaw, key, sizeRaw string
+		if csvFormat {
+			fields := strings.SplitN(line, ",", 3)
+			if len(fields) != 3 {
+				continue
+			}
+			tsRaw, key, sizeRaw = fields[0], fields[1], fields[2]
+		} else {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				continue
+			}
+			tsRaw, key, sizeRaw = fields[0], fields[1], fields[2]
+		}
+
+		tsMs, err := strconv.ParseFloat(tsRaw, 64)
+		if err != nil {
+			// Non-numeric first field: a header row. Skip it rather than
+			// aborting the whole trace.
+			continue
+		}
+		ts := tsMs
+		if csvFormat {
+			ts = tsMs / 1000.0 // generic CSV timestamps are milliseconds
+		}
+
+		size, err := strconv.ParseInt(strings.TrimSpace(sizeRaw), 10, 64)
+		if err != nil {
+			size = 0
+		}
+
+		var delay time.Duration
+		if haveSeen {
+			delay = time.Duration((ts - prevTs) * float64(time.Second))
+			if delay < 0 {
+				delay = 0
+			}
+		}
+		prevTs = ts
+		haveSeen = true
+
+		entries = append(entries, traceEntry{delay: delay, key: key, size: size})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading trace file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("trace file %s contained no usable entries", path)
+	}
+
+	return entries, nil
+}
+
+// reuseDistanceTracker computes, for each replayed key, the number of
+// requests since that key was last seen, counting the current one (its
+// reuse time), using a hash map from key to the request counter's value at
+// its last occurrence. Repeat requests for other keys in between are
+// counted too, so the value is an upper bound on the LRU stack distance
+// rather than the stack distance itself. A key seen for the first time has no prior occurrence, so its
+// distance is reported as -1 rather than a bogus 0 or MaxInt64.
+type reuseDistanceTracker struct {
+	lastSeen map[string]int64
+	counter  int64
+}
+
+func newReuseDistanceTracker() *reuseDistanceTracker {
+	return &reuseDistanceTracker{lastSeen: make(map[string]int64)}
+}
+
+func (t *reuseDistanceTracker) observe(key string) int64 {
+	t.counter++
+	distance := int64(-1)
+	if last, ok := t.lastSeen[key]; ok {
+		distance = t.counter - last
+	}
+	t.lastSeen[key] = t.counter
+	return distance
+}
+
 func main() {
 	target := flag.String("target", "http://localhost:8080", "Target node (deprecated when --targets is set)")
 	targetsFlag := flag.String("targets", "", "Comma-separated list of target nodes (e.g. http://n1:8080,http://n2:8080)")
-	numURLs := flag.Int("urls", 100, "Number of unique URLs")
-	requests := flag.Int("requests", 1000, "Total requests")
-	rate := flag.Float64("rate", 50, "Requests per second")
-	zipf := flag.Float64("zipf", 1.2, "Zipf alpha (must be > 1.0)")
+	numURLs := flag.Int("urls", 100, "Number of unique URLs (ignored when --trace is set)")
+	requests := flag.Int("requests", 1000, "Total requests (ignored when --trace is set)")
+	rate := flag.Float64("rate", 50, "Requests per second (ignored when --trace is set)")
+	zipf := flag.Float64("zipf", 1.2, "Zipf alpha, must be > 1.0 (ignored when --trace is set)")
 	output := flag.String("output", "results.csv", "Output file")
 	seed := flag.Int64("seed", 0, "Random seed (0 = use current time)")
 	origin := flag.String("origin", "https://httpbin.org", "Origin server base URL (use http://localhost:9999 for local mock)")
+	tracePath := flag.String("trace", "", "Replay a request trace instead of synthetic Zipf: Wikipedia CDN format (timestamp url size) or generic CSV (timestamp_ms,key,size_bytes)")
+	speedup := flag.Float64("speedup", 1.0, "Compress trace inter-arrival timing by this factor (only with --trace)")
+	warmup := flag.Int("warmup", 0, "Replay the first N trace entries to prefill the cache before measurement starts (only with --trace)")
 
 	flag.Parse()
 
@@ -66,6 +199,12 @@ func main() {
 	fmt.Printf("Koorde Cache Workload Generator\n")
 	targetNodes := parseTargetList(*targetsFlag, *target)
 	fmt.Printf("Targets: %s\n", strings.Join(targetNodes, ", "))
+
+	if *tracePath != "" {
+		runTraceWorkload(*tracePath, *speedup, *warmup, targetNodes, *output)
+		return
+	}
+
 	fmt.Printf("URLs: %d\n", *numURLs)
 	fmt.Printf("Requests: %d\n", *requests)
 	fmt.Printf("Rate: %.2f req/sec\n", *rate)
@@ -128,10 +267,11 @@ func main() {
 	}
 	defer file.Close()
 
-	file.WriteString("timestamp,url_id,latency_ms,status,cache_status,node_id\n")
+	file.WriteString("timestamp,url_id,latency_ms,status,cache_status,node_id,reuse_distance\n")
 
 	var metrics Metrics
 	var mu sync.Mutex
+	reuse := newReuseDistanceTracker()
 
 	// Generate workload
 	interval := time.Duration(float64(time.Second) / *rate)
@@ -149,8 +289,9 @@ func main() {
 			idx = idx % uint64(len(urls))
 		}
 		url := urls[idx]
+		reuseDistance := reuse.observe(url)
 
-		go func(urlID uint64, url string) {
+		go func(urlID uint64, url string, reuseDistance int64) {
 			reqStart := time.Now()
 
 			targetIdx := int(urlID) % len(targetNodes)
@@ -180,16 +321,17 @@ func main() {
 			}
 
 			mu.Lock()
-			fmt.Fprintf(file, "%s,%d,%.2f,%d,%s,%s\n",
+			fmt.Fprintf(file, "%s,%d,%.2f,%d,%s,%s,%d\n",
 				time.Now().Format(time.RFC3339),
 				urlID,
 				float64(latency.Microseconds())/1000.0,
 				resp.StatusCode,
 				cacheStatus,
 				nodeID,
+				reuseDistance,
 			)
 			mu.Unlock()
-		}(idx, url)
+		}(idx, url, reuseDistance)
 
 		if (i+1)%100 == 0 {
 			fmt.Printf("Progress: %d/%d (%.1f%%)\n",
@@ -219,3 +361,129 @@ func main() {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("\nResults saved to: %s\n", *output)
 }
+
+// runTraceWorkload replays entries loaded from tracePath instead of the
+// synthetic Zipf generator, preserving each entry's key identity and, at
+// speedup 1.0, its original inter-arrival timing. The first warmup entries
+// are replayed up front (sequentially, with no delay) to prefill the cache
+// and are excluded from results.csv and the summary, per --warmup; the
+// reuse-distance tracker still observes them, since they're real accesses
+// that affect the stack distance of everything replayed afterward.
+func runTraceWorkload(tracePath string, speedup float64, warmup int, targetNodes []string, output string) {
+	if speedup <= 0 {
+		fmt.Printf("Error: --speedup must be > 0 (got %.2f)\n", speedup)
+		os.Exit(1)
+	}
+
+	entries, err := parseTrace(tracePath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Trace: %s (%d entries)\n", tracePath, len(entries))
+	fmt.Printf("Speedup: %.2fx\n", speedup)
+	fmt.Printf("Warmup: %d entries\n", warmup)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	if warmup > len(entries) {
+		warmup = len(entries)
+	}
+
+	reuse := newReuseDistanceTracker()
+
+	fireRequest := func(key string) (latencyMs float64, status int, cacheStatus, nodeID string, err error) {
+		targetBase := targetNodes[int(reuse.counter)%len(targetNodes)]
+		fullURL := fmt.Sprintf("%s/cache?url=%s", targetBase, key)
+		reqStart := time.Now()
+		resp, err := http.Get(fullURL)
+		latencyMs = float64(time.Since(reqStart).Microseconds()) / 1000.0
+		if err != nil {
+			return latencyMs, 0, "", "", err
+		}
+		defer resp.Body.Close()
+		return latencyMs, resp.StatusCode, resp.Header.Get("X-Cache"), resp.Header.Get("X-Node-ID"), nil
+	}
+
+	if warmup > 0 {
+		fmt.Printf("Warming up with %d entries...\n", warmup)
+		for _, e := range entries[:warmup] {
+			reuse.observe(e.key)
+			if _, _, _, _, err := fireRequest(e.key); err != nil {
+				fmt.Printf("Warmup request failed for key %q: %v\n", e.key, err)
+			}
+		}
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		fmt.Printf("Error creating file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	file.WriteString("timestamp,url_id,latency_ms,status,cache_status,node_id,reuse_distance\n")
+
+	var metrics Metrics
+	var mu sync.Mutex
+	start := time.Now()
+
+	measured := entries[warmup:]
+	for i, e := range measured {
+		if e.delay > 0 {
+			time.Sleep(time.Duration(float64(e.delay) / speedup))
+		}
+
+		reuseDistance := reuse.observe(e.key)
+
+		latencyMs, status, cacheStatus, nodeID, err := fireRequest(e.key)
+
+		atomic.AddInt64(&metrics.total, 1)
+		if err != nil {
+			atomic.AddInt64(&metrics.failed, 1)
+		} else {
+			atomic.AddInt64(&metrics.success, 1)
+			atomic.AddInt64(&metrics.latency, int64(latencyMs*1e6))
+			if strings.HasPrefix(cacheStatus, "HIT") {
+				atomic.AddInt64(&metrics.hits, 1)
+			} else {
+				atomic.AddInt64(&metrics.misses, 1)
+			}
+		}
+
+		mu.Lock()
+		fmt.Fprintf(file, "%s,%s,%.2f,%d,%s,%s,%d\n",
+			time.Now().Format(time.RFC3339),
+			e.key,
+			latencyMs,
+			status,
+			cacheStatus,
+			nodeID,
+			reuseDistance,
+		)
+		mu.Unlock()
+
+		if (i+1)%100 == 0 {
+			fmt.Printf("Progress: %d/%d (%.1f%%)\n",
+				i+1, len(measured), float64(i+1)/float64(len(measured))*100)
+		}
+	}
+
+	total := atomic.LoadInt64(&metrics.total)
+	success := atomic.LoadInt64(&metrics.success)
+	hits := atomic.LoadInt64(&metrics.hits)
+	misses := atomic.LoadInt64(&metrics.misses)
+	avgLatency := float64(atomic.LoadInt64(&metrics.latency)) / float64(success) / 1e6
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("Summary:")
+	fmt.Printf("  Total: %d\n", total)
+	fmt.Printf("  Success: %d (%.1f%%)\n", success, float64(success)/float64(total)*100)
+	fmt.Printf("  Avg Latency: %.2f ms\n", avgLatency)
+	fmt.Printf("  Cache Hits: %d\n", hits)
+	fmt.Printf("  Cache Misses: %d\n", misses)
+	fmt.Printf("  Hit Rate: %.1f%%\n", float64(hits)/float64(hits+misses)*100)
+	fmt.Printf("  Duration: %s\n", time.Since(start).Round(time.Second))
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("\nResults saved to: %s\n", output)
+}
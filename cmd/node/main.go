@@ -5,12 +5,14 @@ import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	zapfactory "KoordeDHT/internal/logger/zap"
+	"KoordeDHT/internal/metrics"
 	"KoordeDHT/internal/node/cache"
 	"KoordeDHT/internal/node/chord"
 	client2 "KoordeDHT/internal/node/client"
 	"KoordeDHT/internal/node/config"
 	"KoordeDHT/internal/node/dht"
 	logicnode2 "KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/rendezvous"
 	routingtable2 "KoordeDHT/internal/node/routingtable"
 	server2 "KoordeDHT/internal/node/server"
 	"KoordeDHT/internal/node/simple"
@@ -31,9 +33,16 @@ import (
 
 var defaultConfigPath = "config/node/config.yaml"
 
+// adminUsersEnvVar is the environment variable consulted for admin
+// credentials when --admin-users isn't set, for deployments that prefer
+// their secret-injection mechanism over a file on disk.
+const adminUsersEnvVar = "KOORDE_ADMIN_USERS"
+
 func main() {
 	// Parse command-line flags
 	configPath := flag.String("config", defaultConfigPath, "path to configuration file")
+	adminUsersPath := flag.String("admin-users", "", "path to a JSON admin-users credentials file (see server.AdminUser); falls back to the "+adminUsersEnvVar+" env var if unset")
+	statsdAddr := flag.String("statsd", "", "host:port of a UDP statsd collector to push cache/hotspot/routing metrics to (e.g. localhost:8125); disabled if unset")
 	flag.Parse()
 
 	// Load configuration
@@ -126,6 +135,16 @@ func main() {
 	lgr.Info("initialized web cache",
 		logger.F("capacity_mb", cfg.Cache.CapacityMB))
 
+	// Load any snapshot left by a previous run before HTTP serving begins,
+	// so a restart transfers a warm cache instead of forcing cold re-fetches.
+	if cfg.Cache.SnapshotPath != "" {
+		if err := webCache.RestoreFromFile(cfg.Cache.SnapshotPath); err != nil {
+			lgr.Warn("failed to restore cache snapshot", logger.F("path", cfg.Cache.SnapshotPath), logger.F("err", err))
+		} else {
+			lgr.Info("restored cache snapshot", logger.F("path", cfg.Cache.SnapshotPath), logger.F("entries", webCache.Size()))
+		}
+	}
+
 	hotspotDetector := cache.NewHotspotDetector(
 		cfg.Cache.HotspotThreshold,
 		cfg.Cache.HotspotDecayRate,
@@ -145,9 +164,29 @@ func main() {
 
 			stale := hotspotDetector.CleanStale(24 * time.Hour)
 			lgr.Info("cleaned stale hotspot entries", logger.F("count", stale))
+
+			cooled := hotspotDetector.SweepCooling()
+			lgr.Info("cooled hotspot entries", logger.F("count", cooled))
 		}
 	}()
 
+	// Start periodic cache snapshotting, so a later restart has a warm
+	// cache to restore instead of starting cold.
+	if cfg.Cache.SnapshotPath != "" && cfg.Cache.SnapshotInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Cache.SnapshotInterval)
+			defer ticker.Stop()
+			for {
+				<-ticker.C
+				if err := webCache.SnapshotToFile(cfg.Cache.SnapshotPath); err != nil {
+					lgr.Warn("failed to snapshot cache", logger.F("path", cfg.Cache.SnapshotPath), logger.F("err", err))
+					continue
+				}
+				lgr.Debug("snapshotted cache", logger.F("path", cfg.Cache.SnapshotPath), logger.F("entries", webCache.Size()))
+			}
+		}()
+	}
+
 	// Initialize node based on protocol
 	var n dht.DHTNode
 
@@ -179,6 +218,33 @@ func main() {
 		lgr.Info("Initialized Simple hash node",
 			logger.F("cluster_size", len(cfg.DHT.ClusterNodes)))
 
+	case "rendezvous":
+		// Rendezvous (HRW) hashing - requires static cluster membership
+		rendezvousNode := rendezvous.New(
+			&domainNode,
+			space,
+			cp,
+			store,
+			rendezvous.WithLogger(lgr),
+		)
+
+		// Set up cluster nodes if configured
+		if len(cfg.DHT.ClusterNodes) > 0 {
+			clusterNodes := make([]*domain.Node, 0, len(cfg.DHT.ClusterNodes))
+			for _, addr := range cfg.DHT.ClusterNodes {
+				nodeID := space.NewIdFromString(addr)
+				clusterNodes = append(clusterNodes, &domain.Node{
+					ID:   nodeID,
+					Addr: addr,
+				})
+			}
+			rendezvousNode.SetClusterNodes(clusterNodes)
+		}
+
+		n = rendezvousNode
+		lgr.Info("Initialized Rendezvous (HRW) hash node",
+			logger.F("cluster_size", len(cfg.DHT.ClusterNodes)))
+
 	case "chord":
 		chordRT := chord.NewRoutingTable(
 			&domainNode,
@@ -247,6 +313,37 @@ func main() {
 	)
 	lgr.Debug("initialized HTTP cache server", logger.F("port", cfg.Cache.HTTPPort))
 
+	// Load admin credentials, if configured, and gate the admin/topology
+	// routes behind them (see server.routeACL).
+	var adminUsers []server2.AdminUser
+	if *adminUsersPath != "" {
+		adminUsers, err = server2.LoadAdminUsersFile(*adminUsersPath)
+		if err != nil {
+			lgr.Error("failed to load --admin-users file", logger.F("path", *adminUsersPath), logger.F("err", err))
+			os.Exit(1)
+		}
+	} else if _, ok := os.LookupEnv(adminUsersEnvVar); ok {
+		adminUsers, err = server2.LoadAdminUsersEnv(adminUsersEnvVar)
+		if err != nil {
+			lgr.Error("failed to load admin users from environment", logger.F("env", adminUsersEnvVar), logger.F("err", err))
+			os.Exit(1)
+		}
+	}
+	if auth := server2.NewAuthenticatorFromUsers(adminUsers); auth != nil {
+		httpServer.SetAuthenticator(auth)
+		lgr.Info("admin authentication enabled", logger.F("users", len(adminUsers)))
+	} else {
+		lgr.Warn("no --admin-users configured: /cluster/add, /cluster/remove, and /debug are unauthenticated")
+	}
+
+	// Wire the optional statsd emitter (see internal/metrics.StatsdEmitter).
+	if *statsdAddr != "" {
+		statsdEmitter := metrics.NewStatsdEmitter(*statsdAddr, 0, lgr.Named("statsd"))
+		defer statsdEmitter.Stop()
+		httpServer.SetStatsdEmitter(statsdEmitter)
+		lgr.Info("statsd metrics enabled", logger.F("addr", *statsdAddr))
+	}
+
 	// Run gRPC server in background
 	serveErr := make(chan error, 1)
 	go func() { serveErr <- s.Start() }()
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrChecksumMismatch is returned by VerifyResource (and anything that
+// wraps it, such as VerifyIntegrity on a DHTNode) when a Resource's stored
+// value no longer matches its integrity metadata.
+var ErrChecksumMismatch = errors.New("resource checksum mismatch")
+
+const adlerMod = 65521
+
+// Adler32 is a rolling Adler-32 checksum state, following the classic
+// per-byte update rule (s1 = (s1+byte) mod 65521, s2 = (s2+s1) mod 65521).
+// It's exposed as a value type specifically so a future chunked Put can
+// fold bytes into it incrementally as they stream in, instead of buffering
+// the whole value to rehash it.
+type Adler32 struct {
+	s1, s2 uint32
+}
+
+// NewAdler32 returns a fresh Adler-32 state.
+func NewAdler32() Adler32 {
+	return Adler32{s1: 1}
+}
+
+// Update folds data into the checksum. It may be called multiple times to
+// accumulate a checksum incrementally.
+func (a *Adler32) Update(data []byte) {
+	for _, b := range data {
+		a.s1 = (a.s1 + uint32(b)) % adlerMod
+		a.s2 = (a.s2 + a.s1) % adlerMod
+	}
+}
+
+// Sum returns the current Adler-32 checksum value.
+func (a Adler32) Sum() uint32 {
+	return a.s2<<16 | a.s1
+}
+
+// ChecksumResource computes the Adler-32 checksum of value, and, if strong
+// is true, a SHA-256 digest alongside it. Callers use this at Put time to
+// populate Resource.Checksum and Resource.StrongDigest.
+func ChecksumResource(value []byte, strong bool) (checksum uint32, digest []byte) {
+	a := NewAdler32()
+	a.Update(value)
+	checksum = a.Sum()
+	if strong {
+		sum := sha256.Sum256(value)
+		digest = sum[:]
+	}
+	return checksum, digest
+}
+
+// VerifyResource recomputes res.Value's Adler-32 checksum (and its SHA-256
+// digest, if res.StrongDigest is set) and compares them against res's
+// stored integrity metadata, returning ErrChecksumMismatch if either
+// disagrees.
+func VerifyResource(res Resource) error {
+	a := NewAdler32()
+	a.Update(res.Value)
+	if a.Sum() != res.Checksum {
+		return fmt.Errorf("%w: adler-32 mismatch for %s", ErrChecksumMismatch, res.Key.ToHexString(true))
+	}
+	if len(res.StrongDigest) > 0 {
+		sum := sha256.Sum256(res.Value)
+		if !bytes.Equal(sum[:], res.StrongDigest) {
+			return fmt.Errorf("%w: sha-256 mismatch for %s", ErrChecksumMismatch, res.Key.ToHexString(true))
+		}
+	}
+	return nil
+}
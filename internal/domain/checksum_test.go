@@ -0,0 +1,36 @@
+package domain
+
+import "testing"
+
+func TestChecksumResourceRoundTrip(t *testing.T) {
+	value := []byte("the quick brown fox jumps over the lazy dog")
+
+	checksum, digest := ChecksumResource(value, true)
+	res := Resource{Key: ID{0x01}, Value: value, Checksum: checksum, StrongDigest: digest}
+
+	if err := VerifyResource(res); err != nil {
+		t.Fatalf("VerifyResource failed on an untampered resource: %v", err)
+	}
+
+	corrupted := res
+	corrupted.Value = []byte("the quick brown fox jumps over the lazy cat")
+	if err := VerifyResource(corrupted); err == nil {
+		t.Fatal("VerifyResource should have failed on a tampered value")
+	}
+}
+
+func TestAdler32IncrementalMatchesWholeValue(t *testing.T) {
+	value := []byte("incremental checksum test payload")
+
+	whole := NewAdler32()
+	whole.Update(value)
+
+	incremental := NewAdler32()
+	for _, b := range value {
+		incremental.Update([]byte{b})
+	}
+
+	if whole.Sum() != incremental.Sum() {
+		t.Errorf("incremental Adler-32 = %d, expected %d (whole-value)", incremental.Sum(), whole.Sum())
+	}
+}
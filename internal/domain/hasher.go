@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+
+	"lukechampine.com/blake3"
+)
+
+// Hasher computes a digest over arbitrary data. Space uses it to derive
+// identifiers from strings (node addresses, resource keys) independently of
+// any one fixed hash algorithm, so the identifier space is no longer tied
+// to SHA-1 and its 160-bit output.
+type Hasher interface {
+	// Hash returns the digest of data. Space folds or truncates this to
+	// ByteLen bytes, keeping the most significant bytes.
+	Hash(data []byte) []byte
+}
+
+type sha1Hasher struct{}
+
+func (sha1Hasher) Hash(data []byte) []byte {
+	sum := sha1.Sum(data)
+	return sum[:]
+}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Hash(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+type sha512_256Hasher struct{}
+
+func (sha512_256Hasher) Hash(data []byte) []byte {
+	sum := sha512.Sum512_256(data)
+	return sum[:]
+}
+
+type blake3Hasher struct{}
+
+func (blake3Hasher) Hash(data []byte) []byte {
+	sum := blake3.Sum256(data)
+	return sum[:]
+}
+
+var (
+	// SHA1Hasher is the legacy 160-bit hasher. It's the implicit default
+	// for a Space built via NewSpace or a bare Space{} literal, preserving
+	// the historical behavior of NewIdFromString.
+	SHA1Hasher Hasher = sha1Hasher{}
+
+	// SHA256Hasher derives 256-bit digests, suitable for identifier spaces
+	// up to 256 bits.
+	SHA256Hasher Hasher = sha256Hasher{}
+
+	// SHA512_256Hasher derives 256-bit digests using SHA-512/256 (SHA-512
+	// truncated to 256 bits), which runs faster than SHA-256 on 64-bit
+	// hardware while avoiding SHA-512's length-extension-prone full output.
+	SHA512_256Hasher Hasher = sha512_256Hasher{}
+
+	// BLAKE3Hasher derives 256-bit digests using BLAKE3, for deployments
+	// that want the fastest option on large (up to 256-bit) identifier
+	// spaces.
+	BLAKE3Hasher Hasher = blake3Hasher{}
+)
@@ -0,0 +1,66 @@
+package domain
+
+import "testing"
+
+func TestWideSpaceArithmeticWithAlternateHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		bits   int
+		degree int
+		hasher Hasher
+	}{
+		{name: "224-bit SHA256Hasher", bits: 224, degree: 2, hasher: SHA256Hasher},
+		{name: "256-bit SHA256Hasher", bits: 256, degree: 2, hasher: SHA256Hasher},
+		{name: "256-bit SHA512_256Hasher", bits: 256, degree: 4, hasher: SHA512_256Hasher},
+		{name: "256-bit BLAKE3Hasher", bits: 256, degree: 8, hasher: BLAKE3Hasher},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := NewSpaceWithHasher(tt.bits, tt.degree, 8, tt.hasher)
+			if err != nil {
+				t.Fatalf("NewSpaceWithHasher failed: %v", err)
+			}
+
+			a := sp.NewIdFromString("node-a:4000")
+			b := sp.NewIdFromString("node-b:4001")
+
+			if err := sp.IsValidID(a); err != nil {
+				t.Fatalf("derived ID a is invalid: %v", err)
+			}
+			if err := sp.IsValidID(b); err != nil {
+				t.Fatalf("derived ID b is invalid: %v", err)
+			}
+			if a.Equal(b) {
+				t.Fatalf("expected distinct IDs for distinct inputs, got %s for both", a.ToHexString(true))
+			}
+
+			sum, err := sp.AddMod(a, b)
+			if err != nil {
+				t.Fatalf("AddMod failed: %v", err)
+			}
+			if err := sp.IsValidID(sum); err != nil {
+				t.Errorf("AddMod result is invalid: %v", err)
+			}
+
+			prod, err := sp.MulKMod(a)
+			if err != nil {
+				t.Fatalf("MulKMod failed: %v", err)
+			}
+			if err := sp.IsValidID(prod); err != nil {
+				t.Errorf("MulKMod result is invalid: %v", err)
+			}
+
+			digit, rest, err := sp.NextDigitBaseK(a)
+			if err != nil {
+				t.Fatalf("NextDigitBaseK failed: %v", err)
+			}
+			if err := sp.IsValidID(rest); err != nil {
+				t.Errorf("NextDigitBaseK remainder is invalid: %v", err)
+			}
+			if digit >= uint64(tt.degree) {
+				t.Errorf("NextDigitBaseK digit = %d, expected < degree %d", digit, tt.degree)
+			}
+		})
+	}
+}
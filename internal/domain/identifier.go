@@ -2,7 +2,6 @@ package domain
 
 import (
 	"bytes"
-	"crypto/sha1"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -53,6 +52,13 @@ type Space struct {
 	ByteLen      int // Number of bytes needed to represent an identifier
 	GraphGrade   int // Base k of the de Bruijn graph (must be a power of 2)
 	SuccListSize int // Length of the successor list for fault tolerance
+
+	// hasher derives identifiers from strings in NewIdFromString. It's
+	// unexported so a bare Space{} literal (used throughout the tests and
+	// routing-table constructors) can't leave it inconsistent with Bits;
+	// NewIdFromString falls back to SHA1Hasher when it's nil, which keeps
+	// that literal form behaving exactly as it always has.
+	hasher Hasher
 }
 
 // NewSpace initializes a new identifier space for the Koorde DHT.
@@ -85,9 +91,26 @@ func NewSpace(b int, degree int, succListSize int) (Space, error) {
 		ByteLen:      (b + 7) / 8,
 		GraphGrade:   degree,
 		SuccListSize: succListSize,
+		hasher:       SHA1Hasher,
 	}, nil
 }
 
+// NewSpaceWithHasher is identical to NewSpace except that identifiers are
+// derived using h instead of the default SHA1Hasher. This is how a caller
+// opts into a wider or faster hash (SHA256Hasher, SHA512_256Hasher,
+// BLAKE3Hasher) for identifier spaces beyond SHA-1's 160 bits.
+func NewSpaceWithHasher(b int, degree int, succListSize int, h Hasher) (Space, error) {
+	sp, err := NewSpace(b, degree, succListSize)
+	if err != nil {
+		return Space{}, err
+	}
+	if h == nil {
+		return Space{}, fmt.Errorf("invalid hasher: nil")
+	}
+	sp.hasher = h
+	return sp, nil
+}
+
 // -------------------------------
 // ID type and methods
 // -------------------------------
@@ -114,9 +137,13 @@ func (sp Space) Zero() ID {
 // or resource keys.
 //
 // The ID is produced as follows:
-//  1. Compute the SHA-1 digest (160 bits) of the input string.
-//  2. Copy the most significant bytes (big-endian order) into a buffer
-//     of length sp.ByteLen.
+//  1. Compute the digest of the input string using sp.hasher (SHA1Hasher
+//     if sp was built as a bare Space{} literal rather than via NewSpace
+//     or NewSpaceWithHasher).
+//  2. If the digest is at least sp.ByteLen bytes, copy its most significant
+//     bytes (big-endian order) into a buffer of length sp.ByteLen. Otherwise
+//     (a digest shorter than the identifier space, e.g. a 128-bit hasher
+//     feeding a 256-bit space) fold it by tiling it across the buffer.
 //  3. If Bits is not a multiple of 8, mask the unused high-order bits
 //     in the first byte so that the ID falls strictly within the range
 //     [0, 2^Bits - 1].
@@ -124,12 +151,20 @@ func (sp Space) Zero() ID {
 // This ensures the generated ID is uniformly distributed and valid
 // for the configured identifier space.
 func (sp Space) NewIdFromString(s string) ID {
-	// SHA-1 digest of the input
-	h := sha1.Sum([]byte(s)) // returns [20]byte (160 bits)
+	h := sp.hasher
+	if h == nil {
+		h = SHA1Hasher
+	}
+	digest := h.Hash([]byte(s))
 
-	// allocate buffer of correct length and copy MSBs
 	buf := make([]byte, sp.ByteLen)
-	copy(buf, h[:sp.ByteLen])
+	if len(digest) >= sp.ByteLen {
+		copy(buf, digest[:sp.ByteLen])
+	} else {
+		for i := range buf {
+			buf[i] = digest[i%len(digest)]
+		}
+	}
 
 	// mask unused bits if identifier length is not byte-aligned
 	extraBits := sp.ByteLen*8 - sp.Bits
@@ -0,0 +1,20 @@
+package domain
+
+// Resource is a single key/value pair stored in the DHT, plus the integrity
+// metadata computed for it at Put time (see ChecksumResource) and checked
+// again at Get time, on replica handoff during stabilization, and on
+// successor-list handoff (see VerifyResource).
+type Resource struct {
+	Key   ID
+	Value []byte
+
+	// Checksum is the Adler-32 checksum of Value, computed once at Put
+	// time so every hop that re-stores this Resource can cheaply re-check
+	// it without rehashing.
+	Checksum uint32
+
+	// StrongDigest is an optional SHA-256 digest of Value, checked in
+	// addition to Checksum wherever collision resistance matters (e.g. a
+	// VerifyIntegrity scrub pass). Nil means "not computed".
+	StrongDigest []byte
+}
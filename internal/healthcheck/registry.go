@@ -0,0 +1,111 @@
+// Package healthcheck provides a small composable health-check registry,
+// modeled on etcd's health subsystem: named checks classified as Liveness
+// ("restart me if this fails") or Readiness ("don't route to me yet"),
+// run independently of each other and reported with a per-check
+// breakdown.
+package healthcheck
+
+import "context"
+
+// Class classifies what a failing Check implies for the caller.
+type Class int
+
+const (
+	// Liveness checks, when failing, mean the process itself is broken
+	// and should be restarted (e.g. an orchestrator's restart policy).
+	Liveness Class = iota
+	// Readiness checks, when failing, mean the process is fine but not
+	// yet fit to receive traffic (e.g. still bootstrapping).
+	Readiness
+)
+
+func (c Class) String() string {
+	if c == Liveness {
+		return "liveness"
+	}
+	return "readiness"
+}
+
+// Check is a single named health check.
+type Check interface {
+	// Name identifies the check in a Report and in a registry's exclude set.
+	Name() string
+	// Class says whether a failure here means "restart me" or "don't
+	// route to me yet".
+	Class() Class
+	// Check runs the check, returning a non-nil error on failure.
+	Check(ctx context.Context) error
+}
+
+// FuncCheck adapts a name, Class, and plain func into a Check, so callers
+// don't need a dedicated type per check.
+type FuncCheck struct {
+	CheckName  string
+	CheckClass Class
+	Fn         func(ctx context.Context) error
+}
+
+func (f FuncCheck) Name() string                    { return f.CheckName }
+func (f FuncCheck) Class() Class                    { return f.CheckClass }
+func (f FuncCheck) Check(ctx context.Context) error { return f.Fn(ctx) }
+
+// Result is one Check's outcome.
+type Result struct {
+	Name    string `json:"name"`
+	Class   string `json:"class"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report is the outcome of running a set of Checks.
+type Report struct {
+	Healthy bool     `json:"healthy"`
+	Checks  []Result `json:"checks"`
+}
+
+// Registry holds a set of named Checks and runs them, optionally excluding
+// some by name (e.g. "debruijn" while bootstrapping a single-node cluster
+// that legitimately has no de Bruijn neighbors yet).
+type Registry struct {
+	checks []Check
+}
+
+// NewRegistry creates a Registry holding checks.
+func NewRegistry(checks ...Check) *Registry {
+	return &Registry{checks: checks}
+}
+
+// Register adds a Check to the registry.
+func (reg *Registry) Register(c Check) {
+	reg.checks = append(reg.checks, c)
+}
+
+// Run executes every registered Check of the given class whose Name isn't
+// in exclude, returning a Report summarizing all of them.
+func (reg *Registry) Run(ctx context.Context, class Class, exclude map[string]bool) Report {
+	return reg.run(ctx, exclude, func(c Check) bool { return c.Class() == class })
+}
+
+// RunAll runs every registered Check regardless of Class — used by a combined
+// endpoint (e.g. /health) that reports both liveness and readiness in one
+// breakdown.
+func (reg *Registry) RunAll(ctx context.Context, exclude map[string]bool) Report {
+	return reg.run(ctx, exclude, func(Check) bool { return true })
+}
+
+func (reg *Registry) run(ctx context.Context, exclude map[string]bool, include func(Check) bool) Report {
+	report := Report{Healthy: true}
+	for _, c := range reg.checks {
+		if !include(c) || exclude[c.Name()] {
+			continue
+		}
+		result := Result{Name: c.Name(), Class: c.Class().String(), Healthy: true}
+		if err := c.Check(ctx); err != nil {
+			result.Healthy = false
+			result.Error = err.Error()
+			report.Healthy = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
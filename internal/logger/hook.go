@@ -0,0 +1,29 @@
+package logger
+
+import "time"
+
+// Entry is the data passed to a Hook for every log call, modeled on
+// logrus's Entry: everything needed to re-render or re-route the line
+// without re-deriving it from the originating Logger.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Logger  string
+	Message string
+	Fields  []Field
+}
+
+// Hook receives a copy of every Entry logged at one of the levels it
+// declares via Levels, so operators can route ring-membership and other
+// events to external systems (syslog, log files, alerting) without
+// re-instrumenting call sites. Fire should not block for long; it runs
+// synchronously on the logging goroutine.
+type Hook interface {
+	// Levels returns the set of levels this hook wants to see. A hook
+	// interested in everything returns AllLevels.
+	Levels() []Level
+
+	// Fire is called once per matching Entry. An error is not surfaced to
+	// the caller of the log call; it's solely for the hook's own bookkeeping.
+	Fire(entry Entry) error
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileRotationHook appends formatted entries to a log file, rotating it to
+// a numbered backup (path.1, path.2, ...) once it exceeds maxSizeBytes, and
+// keeping at most maxBackups of those around.
+type FileRotationHook struct {
+	mu           sync.Mutex
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+	levels       []Level
+
+	file *os.File
+	size int64
+}
+
+// NewFileRotationHook opens (creating if necessary) the log file at path,
+// returning a hook that rotates it once it grows past maxSizeBytes, keeping
+// at most maxBackups rotated copies. A nil levels defaults to AllLevels.
+func NewFileRotationHook(path string, maxSizeBytes int64, maxBackups int, levels []Level) (*FileRotationHook, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("logger: open log file %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("logger: stat log file %s: %w", path, err)
+	}
+	if levels == nil {
+		levels = AllLevels
+	}
+	return &FileRotationHook{
+		path:         path,
+		maxSizeBytes: maxSizeBytes,
+		maxBackups:   maxBackups,
+		levels:       levels,
+		file:         f,
+		size:         info.Size(),
+	}, nil
+}
+
+func (h *FileRotationHook) Levels() []Level { return h.levels }
+
+func (h *FileRotationHook) Fire(entry Entry) error {
+	line := formatEntry(entry) + "\n"
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxSizeBytes > 0 && h.size+int64(len(line)) > h.maxSizeBytes {
+		if err := h.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := h.file.WriteString(line)
+	h.size += int64(n)
+	return err
+}
+
+// rotateLocked shifts path.(N-1) -> path.N down to path.1, then reopens a
+// fresh, empty file at path. Callers must hold h.mu.
+func (h *FileRotationHook) rotateLocked() error {
+	if err := h.file.Close(); err != nil {
+		return fmt.Errorf("logger: close log file before rotation: %w", err)
+	}
+
+	if h.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", h.path, h.maxBackups))
+	}
+	for i := h.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", h.path, i)
+		dst := fmt.Sprintf("%s.%d", h.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if h.maxBackups > 0 {
+		os.Rename(h.path, h.path+".1")
+	}
+
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: reopen log file after rotation: %w", err)
+	}
+	h.file = f
+	h.size = 0
+	return nil
+}
+
+// Close flushes and closes the underlying file.
+func (h *FileRotationHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
@@ -0,0 +1,46 @@
+package logger
+
+import "sync"
+
+// MemoryHook records every matching Entry in memory, for tests that want to
+// assert a particular event was logged (e.g. that Notify logged a
+// predecessor-change event) without parsing text output.
+type MemoryHook struct {
+	mu      sync.Mutex
+	entries []Entry
+	levels  []Level
+}
+
+// NewMemoryHook returns a MemoryHook recording entries at any of levels. A
+// nil levels defaults to AllLevels.
+func NewMemoryHook(levels ...Level) *MemoryHook {
+	if len(levels) == 0 {
+		levels = AllLevels
+	}
+	return &MemoryHook{levels: levels}
+}
+
+func (h *MemoryHook) Levels() []Level { return h.levels }
+
+func (h *MemoryHook) Fire(entry Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+// Entries returns a snapshot of every entry recorded so far.
+func (h *MemoryHook) Entries() []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]Entry, len(h.entries))
+	copy(out, h.entries)
+	return out
+}
+
+// Reset discards every recorded entry.
+func (h *MemoryHook) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = nil
+}
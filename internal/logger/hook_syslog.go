@@ -0,0 +1,53 @@
+//go:build !windows
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogHook forwards matching entries to a local or remote syslog daemon.
+// It's the logrus syslog hook pattern adapted to our Hook interface: dial
+// once at construction, then write a formatted line per Fire call.
+type SyslogHook struct {
+	writer *syslog.Writer
+	levels []Level
+}
+
+// NewSyslogHook dials the syslog daemon at network/addr (pass "", "" to use
+// the local syslog socket) and returns a hook that forwards entries at any
+// of levels. A nil levels defaults to AllLevels.
+func NewSyslogHook(network, addr string, priority syslog.Priority, tag string, levels []Level) (*SyslogHook, error) {
+	w, err := syslog.Dial(network, addr, priority, tag)
+	if err != nil {
+		return nil, fmt.Errorf("logger: dial syslog: %w", err)
+	}
+	if levels == nil {
+		levels = AllLevels
+	}
+	return &SyslogHook{writer: w, levels: levels}, nil
+}
+
+func (h *SyslogHook) Levels() []Level { return h.levels }
+
+func (h *SyslogHook) Fire(entry Entry) error {
+	line := formatEntry(entry)
+	switch entry.Level {
+	case DebugLevel:
+		return h.writer.Debug(line)
+	case InfoLevel:
+		return h.writer.Info(line)
+	case WarnLevel:
+		return h.writer.Warning(line)
+	case ErrorLevel:
+		return h.writer.Err(line)
+	default:
+		return h.writer.Info(line)
+	}
+}
+
+// Close releases the underlying syslog connection.
+func (h *SyslogHook) Close() error {
+	return h.writer.Close()
+}
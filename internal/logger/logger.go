@@ -0,0 +1,93 @@
+// Package logger defines the structured logging interface used throughout
+// KoordeDHT, plus a minimal default implementation. Production binaries
+// normally install a richer adapter (see internal/logger/zap) via
+// WithLogger-style options; NopLogger and the default text logger exist so
+// every package can take a logger.Logger without depending on a concrete
+// backend.
+package logger
+
+import (
+	"KoordeDHT/internal/domain"
+)
+
+// Level identifies the severity of a log entry, ordered from least to most
+// severe so a Hook can filter on a minimum level if it wants to.
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+// String returns the lower-case name of the level, as used in log output.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// AllLevels lists every Level, for hooks that want to fire on all of them.
+var AllLevels = []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel}
+
+// Field is a single structured key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F builds a Field from an arbitrary key/value pair.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// FNode builds a Field describing a DHT node, using its address since that's
+// what's useful to grep for in logs. A nil node logs as an explicit "<nil>"
+// rather than being silently dropped from the field list.
+func FNode(key string, node *domain.Node) Field {
+	if node == nil {
+		return Field{Key: key, Value: "<nil>"}
+	}
+	return Field{Key: key, Value: node.Addr}
+}
+
+// Logger is the structured logging interface implemented by every logging
+// backend used in KoordeDHT (the default text logger, NopLogger, and
+// adapters such as internal/logger/zap).
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+
+	// Named returns a child logger that prefixes its entries with name,
+	// e.g. lgr.Named("stabilizer").
+	Named(name string) Logger
+
+	// WithNode returns a child logger that tags every entry with node's
+	// identity, so log lines from a given node can be filtered in
+	// aggregate logging without passing logger.FNode at every call site.
+	WithNode(node domain.Node) Logger
+}
+
+// NopLogger discards every log entry. It's used as the zero-value default
+// for constructors and in tests that don't care about log output.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...Field) {}
+func (NopLogger) Info(string, ...Field)  {}
+func (NopLogger) Warn(string, ...Field)  {}
+func (NopLogger) Error(string, ...Field) {}
+
+func (n *NopLogger) Named(string) Logger        { return n }
+func (n *NopLogger) WithNode(domain.Node) Logger { return n }
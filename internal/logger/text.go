@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"KoordeDHT/internal/domain"
+)
+
+// TextLogger is the default Logger implementation: it writes
+// "time level name: msg key=value ..." lines to an io.Writer and fans every
+// entry out to any Hooks registered via AddHook. It's deliberately simple —
+// production deployments that want a richer backend (sampling, JSON
+// encoding, log shipping) should use an adapter such as
+// internal/logger/zap instead.
+type TextLogger struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	name   string
+	fields []Field
+
+	hooksMu *sync.RWMutex
+	hooks   map[Level][]Hook
+}
+
+// New creates a TextLogger writing to out. Passing a nil out defaults to
+// os.Stderr.
+func New(out io.Writer) *TextLogger {
+	if out == nil {
+		out = os.Stderr
+	}
+	return &TextLogger{
+		mu:      &sync.Mutex{},
+		out:     out,
+		hooksMu: &sync.RWMutex{},
+		hooks:   make(map[Level][]Hook),
+	}
+}
+
+// AddHook registers h so it fires on every subsequent entry at any of the
+// levels h.Levels() returns. AddHook is only available on TextLogger (not
+// on the Logger interface) since not every backend can usefully support
+// third-party hooks — e.g. a zap adapter would rather use zap's own core
+// composition for this.
+func (l *TextLogger) AddHook(h Hook) {
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+	for _, lvl := range h.Levels() {
+		l.hooks[lvl] = append(l.hooks[lvl], h)
+	}
+}
+
+func (l *TextLogger) Debug(msg string, fields ...Field) { l.log(DebugLevel, msg, fields) }
+func (l *TextLogger) Info(msg string, fields ...Field)  { l.log(InfoLevel, msg, fields) }
+func (l *TextLogger) Warn(msg string, fields ...Field)  { l.log(WarnLevel, msg, fields) }
+func (l *TextLogger) Error(msg string, fields ...Field) { l.log(ErrorLevel, msg, fields) }
+
+func (l *TextLogger) Named(name string) Logger {
+	childName := name
+	if l.name != "" {
+		childName = l.name + "." + name
+	}
+	return l.derive(childName, append([]Field(nil), l.fields...))
+}
+
+func (l *TextLogger) WithNode(node domain.Node) Logger {
+	return l.derive(l.name, append(append([]Field(nil), l.fields...), FNode("node", &node)))
+}
+
+// derive builds a child TextLogger sharing l's out/hooks and the *same*
+// mu/hooksMu pointers, rather than copying *l (or its mutexes) by value —
+// TextLogger holds a sync.Mutex and a sync.RWMutex, and copying those along
+// with the struct would leave parent and child logging through the same
+// already-live lock state without actually serializing on it (go vet:
+// "assignment copies lock value"). Sharing the pointers instead means every
+// logger derived from the same root still serializes writes to the shared
+// out and reads/writes of the shared hooks map through one real lock each,
+// not a fresh one per instance.
+func (l *TextLogger) derive(name string, fields []Field) *TextLogger {
+	return &TextLogger{
+		mu:      l.mu,
+		out:     l.out,
+		name:    name,
+		fields:  fields,
+		hooksMu: l.hooksMu,
+		hooks:   l.hooks,
+	}
+}
+
+func (l *TextLogger) log(level Level, msg string, fields []Field) {
+	all := append(append([]Field(nil), l.fields...), fields...)
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Logger:  l.name,
+		Message: msg,
+		Fields:  all,
+	}
+
+	l.mu.Lock()
+	fmt.Fprintln(l.out, formatEntry(entry))
+	l.mu.Unlock()
+
+	l.fireHooks(entry)
+}
+
+func (l *TextLogger) fireHooks(entry Entry) {
+	l.hooksMu.RLock()
+	hooks := l.hooks[entry.Level]
+	l.hooksMu.RUnlock()
+	for _, h := range hooks {
+		// A hook failing to fire (e.g. syslog unreachable) must not stop the
+		// entry from being logged locally or break the caller; drop it.
+		_ = h.Fire(entry)
+	}
+}
+
+func formatEntry(entry Entry) string {
+	s := entry.Time.Format(time.RFC3339) + " [" + entry.Level.String() + "]"
+	if entry.Logger != "" {
+		s += " " + entry.Logger
+	}
+	s += ": " + entry.Message
+	for _, f := range entry.Fields {
+		s += fmt.Sprintf(" %s=%v", f.Key, f.Value)
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+// Package metrics exposes a DHTNode's RoutingMetrics, plus any
+// implementation-specific series it chooses to contribute, as a
+// Prometheus Collector. The existing /metrics HTTP endpoint
+// (server.HTTPCacheServer) returns a JSON snapshot for the cache-client CLI;
+// this package is the Prometheus-exposition counterpart, for scraping by
+// a real Prometheus server.
+package metrics
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/dht"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "koorde"
+
+// MetricsSource is implemented by a DHT backend that wants to contribute
+// Prometheus series beyond the common DHTNode surface — e.g. Koorde's
+// per-mode hop-count histograms and de Bruijn pointer freshness, neither of
+// which Chord or the simple hash backend have an equivalent for.
+// Implementing it is optional: Exporter type-asserts the DHTNode passed to
+// NewExporter, and simply omits the extra series if the assertion fails.
+type MetricsSource interface {
+	// CollectExtra sends implementation-specific metrics to ch, tagging
+	// them with nodeID for consistency with Exporter's own series.
+	CollectExtra(ch chan<- prometheus.Metric, nodeID string)
+}
+
+// Exporter is a prometheus.Collector wrapping a single DHTNode. Every
+// Collect call reads the node's live state, so scraped values always
+// reflect the node's current routing metrics rather than a value computed
+// at registration time.
+type Exporter struct {
+	node dht.DHTNode
+
+	successorCount          *prometheus.Desc
+	successorListCapacity   *prometheus.Desc
+	deBruijnCount           *prometheus.Desc
+	estimatedNetworkSize    *prometheus.Desc
+	localResourceCount      *prometheus.Desc
+	stabilizeDurationSecs   *prometheus.Desc
+	debruijnEventsTotal     *prometheus.Desc
+	debruijnLatencySeconds  *prometheus.Desc
+	checksumMismatch        *prometheus.Desc
+	keyRedistributionsTotal *prometheus.Desc
+}
+
+// NewExporter wraps node as a Prometheus Collector. Every metric is tagged
+// with node_id, the node's short ID (the first 8 hex characters of
+// Self().ID), so series scraped from different nodes through the same
+// federation target stay distinct.
+func NewExporter(node dht.DHTNode) *Exporter {
+	labels := []string{"node_id", "protocol"}
+	desc := func(name, help string, extraLabels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, append(append([]string{}, labels...), extraLabels...), nil)
+	}
+	return &Exporter{
+		node:                  node,
+		successorCount:        desc("successor_count", "Current number of entries in the node's successor list."),
+		successorListCapacity: desc("successor_list_capacity", "Configured successor list size (Space.SuccListSize)."),
+		deBruijnCount:         desc("debruijn_count", "Current number of de Bruijn neighbors known.", "required"),
+		estimatedNetworkSize:  desc("estimated_network_size", "Estimated number of nodes in the ring (DHTNode.EstimateNetworkSize)."),
+		localResourceCount:    desc("local_resource_count", "Number of resources stored locally on this node."),
+		stabilizeDurationSecs: desc("stabilize_duration_seconds", "Average stabilization round duration in seconds."),
+		debruijnEventsTotal:   desc("debruijn_events_total", "Routing lookups by outcome (success, failure, successor_fallback).", "outcome"),
+		debruijnLatencySeconds: desc("debruijn_latency_seconds", "Routing lookup latency in seconds, by outcome, reconstructed from the fixed log-linear bucket layout in dht.BucketUpperBoundNs.", "outcome"),
+		checksumMismatch:        desc("checksum_mismatch_total", "Locally stored resources that failed their Adler-32/SHA-256 integrity check."),
+		keyRedistributionsTotal: desc("key_redistributions_total", "Membership edits that forced a key remap (only populated by backends without consistent hashing, e.g. simple.Node)."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.successorCount
+	ch <- e.successorListCapacity
+	ch <- e.deBruijnCount
+	ch <- e.estimatedNetworkSize
+	ch <- e.localResourceCount
+	ch <- e.stabilizeDurationSecs
+	ch <- e.debruijnEventsTotal
+	ch <- e.debruijnLatencySeconds
+	ch <- e.checksumMismatch
+	ch <- e.keyRedistributionsTotal
+}
+
+// Collect implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	nodeID := "unknown"
+	if self := e.node.Self(); self != nil {
+		nodeID = shortID(self.ID)
+	}
+
+	m := e.node.RoutingMetrics()
+	labels := []string{nodeID, m.Protocol}
+	required := fmt.Sprintf("%d", e.node.Space().GraphGrade)
+
+	ch <- prometheus.MustNewConstMetric(e.successorCount, prometheus.GaugeValue, float64(len(e.node.SuccessorList())), labels...)
+	ch <- prometheus.MustNewConstMetric(e.successorListCapacity, prometheus.GaugeValue, float64(e.node.Space().SuccListSize), labels...)
+	ch <- prometheus.MustNewConstMetric(e.deBruijnCount, prometheus.GaugeValue, float64(len(e.node.DeBruijnList())), append(append([]string{}, labels...), required)...)
+	ch <- prometheus.MustNewConstMetric(e.estimatedNetworkSize, prometheus.GaugeValue, float64(e.node.EstimateNetworkSize()), labels...)
+	ch <- prometheus.MustNewConstMetric(e.localResourceCount, prometheus.GaugeValue, float64(len(e.node.GetAllResourceStored())), labels...)
+	ch <- prometheus.MustNewConstMetric(e.stabilizeDurationSecs, prometheus.GaugeValue, m.AvgStabilizationRoundMs/1000, labels...)
+	ch <- prometheus.MustNewConstMetric(e.checksumMismatch, prometheus.CounterValue, float64(m.ChecksumMismatchCount), labels...)
+	ch <- prometheus.MustNewConstMetric(e.keyRedistributionsTotal, prometheus.CounterValue, float64(m.KeyRedistributionCount), labels...)
+
+	outcomes := []struct {
+		name    string
+		count   uint64
+		buckets []uint64
+	}{
+		{"success", m.DeBruijnSuccessCount, m.DeBruijnSuccessBucketCounts},
+		{"failure", m.DeBruijnFailureCount, m.DeBruijnFailureBucketCounts},
+		{"successor_fallback", m.SuccessorFallbackCount, m.SuccessorFallbackBucketCounts},
+	}
+	for _, o := range outcomes {
+		outcomeLabels := append(append([]string{}, labels...), o.name)
+		ch <- prometheus.MustNewConstMetric(e.debruijnEventsTotal, prometheus.CounterValue, float64(o.count), outcomeLabels...)
+		if len(o.buckets) > 0 {
+			ch <- constHistogramFromBuckets(e.debruijnLatencySeconds, o.buckets, outcomeLabels...)
+		}
+	}
+
+	if src, ok := e.node.(MetricsSource); ok {
+		src.CollectExtra(ch, nodeID)
+	}
+}
+
+// constHistogramFromBuckets turns a RoutingMetrics *BucketCounts slice (see
+// dht.BucketUpperBoundNs) into a prometheus.Metric histogram. The sum is an
+// approximation — each bucket's count is weighted by its upper bound rather
+// than the true observation values, which a fixed-bucket histogram never
+// retains — but the bucket boundaries themselves are exact, so le-based
+// queries (quantile estimates, SLO burn rates) are accurate.
+func constHistogramFromBuckets(desc *prometheus.Desc, counts []uint64, labels ...string) prometheus.Metric {
+	buckets := make(map[float64]uint64, len(counts)-1)
+	var cum uint64
+	var sum float64
+	for i, c := range counts {
+		cum += c
+		upperSec := float64(dht.BucketUpperBoundNs(i)) / 1e9
+		if i < len(counts)-1 {
+			buckets[upperSec] = cum
+		}
+		sum += float64(c) * upperSec
+	}
+	return prometheus.MustNewConstHistogram(desc, cum, sum, buckets, labels...)
+}
+
+// shortID truncates id's hex representation to 8 characters, matching the
+// node_id tag convention used across all Exporter series.
+func shortID(id domain.ID) string {
+	s := id.ToHexString(false)
+	if len(s) > 8 {
+		return s[:8]
+	}
+	return s
+}
+
+// NewHandler returns an http.Handler serving node's metrics, and any
+// MetricsSource extras it contributes, in the Prometheus exposition
+// format. Callers typically mount it at /metrics on the node's HTTP server.
+func NewHandler(node dht.DHTNode) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewExporter(node))
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
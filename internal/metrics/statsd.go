@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"KoordeDHT/internal/logger"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultStatsdFlushInterval is how often a StatsdEmitter with no
+// explicit interval pushes its accumulated series over UDP.
+const defaultStatsdFlushInterval = 10 * time.Second
+
+// defaultStatsdQueueSize bounds the channel Counter/Gauge/Timer handles
+// enqueue onto; a full queue drops the event rather than blocking the
+// caller, so a stalled or absent statsd collector never backs up into
+// request-handling code.
+const defaultStatsdQueueSize = 4096
+
+// statsdEventKind distinguishes the three statsd series types.
+type statsdEventKind int
+
+const (
+	statsdCounter statsdEventKind = iota
+	statsdGauge
+	statsdTimer
+)
+
+// statsdEvent is one value update, queued by a Counter/Gauge/Timer handle
+// so its caller never touches the UDP socket or even knows statsd exists.
+type statsdEvent struct {
+	name  string
+	kind  statsdEventKind
+	value float64 // Counter: amount to add. Gauge: value to set. Timer: observed milliseconds.
+}
+
+// Counter is a monotonic series handle returned by
+// StatsdEmitter.RegisterCounter. Safe for concurrent use.
+type Counter struct {
+	name    string
+	emitter *StatsdEmitter
+}
+
+// Inc adds 1 to the counter.
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add adds delta to the counter, accumulated until the next flush.
+func (c *Counter) Add(delta float64) {
+	c.emitter.enqueue(statsdEvent{name: c.name, kind: statsdCounter, value: delta})
+}
+
+// Gauge is a point-in-time series handle returned by
+// StatsdEmitter.RegisterGauge. Safe for concurrent use.
+type Gauge struct {
+	name    string
+	emitter *StatsdEmitter
+}
+
+// Set overwrites the gauge's current value, reported at every flush until
+// Set is called again.
+func (g *Gauge) Set(v float64) {
+	g.emitter.enqueue(statsdEvent{name: g.name, kind: statsdGauge, value: v})
+}
+
+// Timer is a duration series handle returned by
+// StatsdEmitter.RegisterTimer. Safe for concurrent use.
+type Timer struct {
+	name    string
+	emitter *StatsdEmitter
+}
+
+// Observe records one duration sample; each flush reports the mean of
+// whatever samples arrived since the previous flush.
+func (t *Timer) Observe(d time.Duration) {
+	t.emitter.enqueue(statsdEvent{name: t.name, kind: statsdTimer, value: float64(d.Milliseconds())})
+}
+
+// StatsdEmitter periodically pushes counters, gauges, and timers to a UDP
+// statsd collector. Series are fed through Counter/Gauge/Timer handles
+// obtained from RegisterCounter/RegisterGauge/RegisterTimer, so callers in
+// cache, routing, or hotspot code never import a statsd client directly —
+// the same "expose a narrow handle, own the transport here" shape
+// cache.EventBus's Subscription uses for its subscribers.
+//
+// The UDP socket is dialed once at construction and never retried: if the
+// collector is unreachable, writes simply fail and are dropped, matching
+// how any real statsd agent is used (fire-and-forget, never a caller's
+// problem).
+type StatsdEmitter struct {
+	lgr           logger.Logger
+	addr          string
+	flushInterval time.Duration
+	events        chan statsdEvent
+	conn          net.Conn // nil if dialing addr failed; writes are then no-ops
+	stop          chan struct{}
+
+	mu         sync.Mutex
+	counters   map[string]float64
+	gauges     map[string]float64
+	timerSum   map[string]float64
+	timerCount map[string]int64
+
+	lastSampleMu sync.RWMutex
+	lastSample   map[string]float64
+}
+
+// NewStatsdEmitter dials addr (host:port) over UDP and starts the flush
+// goroutine immediately; call Stop to end it. flushInterval <= 0 uses
+// defaultStatsdFlushInterval. Dialing UDP never blocks on the network and
+// succeeds even with nothing listening, so a down or misconfigured
+// collector is only ever discovered as silently-dropped packets.
+func NewStatsdEmitter(addr string, flushInterval time.Duration, lgr logger.Logger) *StatsdEmitter {
+	if flushInterval <= 0 {
+		flushInterval = defaultStatsdFlushInterval
+	}
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		lgr.Warn("StatsdEmitter: failed to dial UDP collector, metrics will be dropped",
+			logger.F("addr", addr), logger.F("err", err))
+		conn = nil
+	}
+
+	e := &StatsdEmitter{
+		lgr:           lgr,
+		addr:          addr,
+		flushInterval: flushInterval,
+		events:        make(chan statsdEvent, defaultStatsdQueueSize),
+		conn:          conn,
+		stop:          make(chan struct{}),
+		counters:      make(map[string]float64),
+		gauges:        make(map[string]float64),
+		timerSum:      make(map[string]float64),
+		timerCount:    make(map[string]int64),
+		lastSample:    make(map[string]float64),
+	}
+	go e.run()
+	return e
+}
+
+// RegisterCounter returns a handle for a named counter series.
+func (e *StatsdEmitter) RegisterCounter(name string) *Counter {
+	return &Counter{name: name, emitter: e}
+}
+
+// RegisterGauge returns a handle for a named gauge series.
+func (e *StatsdEmitter) RegisterGauge(name string) *Gauge {
+	return &Gauge{name: name, emitter: e}
+}
+
+// RegisterTimer returns a handle for a named timer series.
+func (e *StatsdEmitter) RegisterTimer(name string) *Timer {
+	return &Timer{name: name, emitter: e}
+}
+
+// enqueue drops ev rather than blocking the caller when the event queue is
+// full, so a slow flush never stalls whoever's recording metrics.
+func (e *StatsdEmitter) enqueue(ev statsdEvent) {
+	select {
+	case e.events <- ev:
+	default:
+	}
+}
+
+// run drains events into the local aggregates and flushes them to addr
+// every flushInterval, until Stop closes e.stop.
+func (e *StatsdEmitter) run() {
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev := <-e.events:
+			e.apply(ev)
+		case <-ticker.C:
+			e.flush()
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+func (e *StatsdEmitter) apply(ev statsdEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch ev.kind {
+	case statsdCounter:
+		e.counters[ev.name] += ev.value
+	case statsdGauge:
+		e.gauges[ev.name] = ev.value
+	case statsdTimer:
+		e.timerSum[ev.name] += ev.value
+		e.timerCount[ev.name]++
+	}
+}
+
+// flush renders every series accumulated since the previous flush as
+// statsd line-protocol text, writes it as one UDP datagram, and records
+// the rendered values as LastSample. Counters and timers reset to zero;
+// gauges persist at their last-Set value until overwritten, matching
+// standard statsd semantics.
+func (e *StatsdEmitter) flush() {
+	e.mu.Lock()
+	counters := e.counters
+	timerSum := e.timerSum
+	timerCount := e.timerCount
+	gauges := make(map[string]float64, len(e.gauges))
+	for name, v := range e.gauges {
+		gauges[name] = v
+	}
+	e.counters = make(map[string]float64)
+	e.timerSum = make(map[string]float64)
+	e.timerCount = make(map[string]int64)
+	e.mu.Unlock()
+
+	sample := make(map[string]float64, len(counters)+len(gauges)+len(timerSum))
+	var buf strings.Builder
+
+	for name, v := range counters {
+		fmt.Fprintf(&buf, "%s:%g|c\n", name, v)
+		sample[name] = v
+	}
+	for name, v := range gauges {
+		fmt.Fprintf(&buf, "%s:%g|g\n", name, v)
+		sample[name] = v
+	}
+	for name, sum := range timerSum {
+		count := timerCount[name]
+		if count == 0 {
+			continue
+		}
+		mean := sum / float64(count)
+		fmt.Fprintf(&buf, "%s:%g|ms\n", name, mean)
+		sample[name] = mean
+	}
+
+	e.lastSampleMu.Lock()
+	e.lastSample = sample
+	e.lastSampleMu.Unlock()
+
+	if e.conn == nil || buf.Len() == 0 {
+		return
+	}
+	if _, err := e.conn.Write([]byte(buf.String())); err != nil {
+		e.lgr.Debug("StatsdEmitter: UDP write failed, dropping sample",
+			logger.F("addr", e.addr), logger.F("err", err))
+	}
+}
+
+// LastSample returns a snapshot of every series value as of the most
+// recent flush, for local inspection (see the cache-client CLI's `stats`
+// command) without needing a real statsd collector running.
+func (e *StatsdEmitter) LastSample() map[string]float64 {
+	e.lastSampleMu.RLock()
+	defer e.lastSampleMu.RUnlock()
+	out := make(map[string]float64, len(e.lastSample))
+	for k, v := range e.lastSample {
+		out[k] = v
+	}
+	return out
+}
+
+// Stop ends the flush goroutine and closes the UDP socket.
+func (e *StatsdEmitter) Stop() {
+	close(e.stop)
+	if e.conn != nil {
+		e.conn.Close()
+	}
+}
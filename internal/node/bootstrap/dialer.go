@@ -0,0 +1,178 @@
+// Package bootstrap provides a dial scheduler for Chord bootstrap peers,
+// inspired by go-ethereum's dialstate: it remembers prior dial outcomes per
+// address and applies a bounded exponential backoff so a dead peer is not
+// retried in a tight loop by Join. The stabilization loop does not consult
+// it: stabilize dials the live successor on every tick.
+package bootstrap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultInitialResolveDelay is the backoff applied after the first
+	// failed dial to a peer.
+	DefaultInitialResolveDelay = 30 * time.Second
+	// DefaultMaxResolveDelay caps the backoff so a long-dead peer is still
+	// retried periodically rather than abandoned forever.
+	DefaultMaxResolveDelay = time.Hour
+)
+
+// dialHistory tracks the outcome of dials to a single peer address.
+type dialHistory struct {
+	lastAttempt time.Time
+	backoff     time.Duration
+	failures    int
+}
+
+// Dialer schedules dial attempts to a set of bootstrap peer addresses,
+// keeping a dialHistory per address so that a peer that keeps failing is
+// retried with exponentially increasing backoff (starting at
+// initialResolveDelay, doubling on each consecutive failure up to
+// maxResolveDelay) instead of being re-dialed on every Join pass.
+type Dialer struct {
+	mu                  sync.Mutex
+	history             map[string]*dialHistory
+	initialResolveDelay time.Duration
+	maxResolveDelay     time.Duration
+
+	attempts     *prometheus.CounterVec
+	successes    *prometheus.CounterVec
+	backoffSkips *prometheus.CounterVec
+}
+
+// NewDialer creates a Dialer with the given initial and maximum backoff
+// delays. Passing zero values falls back to DefaultInitialResolveDelay and
+// DefaultMaxResolveDelay.
+func NewDialer(initialResolveDelay, maxResolveDelay time.Duration) *Dialer {
+	if initialResolveDelay <= 0 {
+		initialResolveDelay = DefaultInitialResolveDelay
+	}
+	if maxResolveDelay <= 0 {
+		maxResolveDelay = DefaultMaxResolveDelay
+	}
+
+	return &Dialer{
+		history:             make(map[string]*dialHistory),
+		initialResolveDelay: initialResolveDelay,
+		maxResolveDelay:     maxResolveDelay,
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "koorde",
+			Subsystem: "bootstrap",
+			Name:      "dial_attempts_total",
+			Help:      "Dial attempts issued per bootstrap peer.",
+		}, []string{"peer"}),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "koorde",
+			Subsystem: "bootstrap",
+			Name:      "dial_successes_total",
+			Help:      "Successful dials per bootstrap peer.",
+		}, []string{"peer"}),
+		backoffSkips: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "koorde",
+			Subsystem: "bootstrap",
+			Name:      "dial_backoff_skips_total",
+			Help:      "Dial attempts skipped because a peer is still within its backoff window.",
+		}, []string{"peer"}),
+	}
+}
+
+// Collectors returns the Prometheus collectors owned by this Dialer so
+// callers can register them on a *prometheus.Registry.
+func (d *Dialer) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{d.attempts, d.successes, d.backoffSkips}
+}
+
+// Eligible reports whether addr may be dialed right now, i.e. it has never
+// been dialed or its backoff window has elapsed since the last attempt.
+func (d *Dialer) Eligible(addr string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.eligibleLocked(addr, time.Now())
+}
+
+func (d *Dialer) eligibleLocked(addr string, now time.Time) bool {
+	h, ok := d.history[addr]
+	if !ok {
+		return true
+	}
+	return now.Sub(h.lastAttempt) >= h.backoff
+}
+
+// Next scans addrs in order and returns the first one currently eligible for
+// a dial, recording a backoff-skip for every ineligible address it passes
+// over. Callers (Join) should dial the returned
+// address and report the outcome via RecordSuccess/RecordFailure.
+func (d *Dialer) Next(addrs []string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for _, addr := range addrs {
+		if d.eligibleLocked(addr, now) {
+			return addr, true
+		}
+		d.backoffSkips.WithLabelValues(addr).Inc()
+	}
+	return "", false
+}
+
+// RecordAttempt marks addr as having been dialed just now, ahead of knowing
+// the outcome. It is safe to skip and only call RecordSuccess/RecordFailure,
+// but recording the attempt up front keeps the Prometheus attempt counter
+// accurate even if the caller forgets to report a result.
+func (d *Dialer) RecordAttempt(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.touchLocked(addr)
+	d.attempts.WithLabelValues(addr).Inc()
+}
+
+// RecordSuccess resets addr's backoff to the initial delay so a peer that
+// just answered is retried promptly if it drops again later.
+func (d *Dialer) RecordSuccess(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h := d.touchLocked(addr)
+	h.backoff = d.initialResolveDelay
+	h.failures = 0
+	d.successes.WithLabelValues(addr).Inc()
+}
+
+// RecordFailure doubles addr's backoff (starting from initialResolveDelay on
+// the first failure), capped at maxResolveDelay.
+func (d *Dialer) RecordFailure(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h := d.touchLocked(addr)
+	h.failures++
+	if h.failures <= 1 {
+		h.backoff = d.initialResolveDelay
+	} else {
+		h.backoff *= 2
+		if h.backoff > d.maxResolveDelay {
+			h.backoff = d.maxResolveDelay
+		}
+	}
+}
+
+func (d *Dialer) touchLocked(addr string) *dialHistory {
+	h, ok := d.history[addr]
+	if !ok {
+		h = &dialHistory{backoff: d.initialResolveDelay}
+		d.history[addr] = h
+	}
+	h.lastAttempt = time.Now()
+	return h
+}
+
+// RemoveStatic purges addr's dial history so operators can force an
+// immediate reconnect attempt regardless of any outstanding backoff.
+func (d *Dialer) RemoveStatic(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.history, addr)
+}
@@ -18,6 +18,23 @@ type Entry struct {
 	CreatedAt   time.Time
 	AccessCount int64
 	element     *list.Element // Pointer for O(1) LRU operations
+
+	// ETag and LastModified are the origin's validators (RFC 7234 §4.3),
+	// carried forward so a stale entry can be conditionally revalidated
+	// with If-None-Match/If-Modified-Since instead of refetching the body.
+	ETag         string
+	LastModified string
+
+	// CacheControl is the origin's parsed Cache-Control directives for
+	// this response, kept around so a 304 revalidation can recompute
+	// freshness the same way the original response did.
+	CacheControl CacheControl
+
+	// StaleUntil is the end of this entry's stale-while-revalidate window
+	// (zero if the origin didn't send one). Between Expiration and
+	// StaleUntil the entry may still be served, with a revalidation
+	// kicked off in the background.
+	StaleUntil time.Time
 }
 
 // WebCache is a thread-safe LRU cache with TTL expiration and capacity limits
@@ -33,6 +50,21 @@ type WebCache struct {
 	misses    int64
 	evictions int64
 	stores    int64
+
+	// events receives an invalidated/evicted CacheEvent for every URL this
+	// cache removes through Delete, expiry, or LRU eviction (Clear publishes
+	// nothing), if set via SetEventBus. nil by default, so WebCache
+	// carries no publishing cost unless a caller opts in.
+	events *EventBus
+}
+
+// SetEventBus installs the EventBus that Delete/evictEntry publish
+// invalidation and eviction events to. Passing nil (the default) disables
+// publishing.
+func (wc *WebCache) SetEventBus(bus *EventBus) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	wc.events = bus
 }
 
 // NewWebCache creates a new cache with the given capacity in megabytes
@@ -61,7 +93,7 @@ func (wc *WebCache) Get(url string) (*Entry, bool) {
 	// Check expiration
 	if time.Now().After(entry.Expiration) {
 		// Expired, remove it
-		wc.evictEntry(url)
+		wc.evictEntry(url, "expired")
 		wc.misses++
 		return nil, false
 	}
@@ -74,6 +106,28 @@ func (wc *WebCache) Get(url string) (*Entry, bool) {
 	return entry, true
 }
 
+// Peek returns a snapshot of an entry regardless of freshness, without
+// evicting it and without updating LRU order or hit/miss metrics.
+// Conditional-revalidation and stale-while-revalidate logic need to see a
+// stale-but-present entry without Get's "expired means evict" side
+// effect.
+//
+// The returned *Entry is a copy, not the one live in the map: a caller
+// that holds onto it (e.g. across a background revalidation, or past the
+// point where a concurrent Put/RefreshValidated could mutate the real
+// entry's fields) must not race with this cache's own writes.
+func (wc *WebCache) Peek(url string) (*Entry, bool) {
+	wc.mu.RLock()
+	defer wc.mu.RUnlock()
+
+	entry, ok := wc.entries[url]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *entry
+	return &snapshot, true
+}
+
 // Put inserts or updates an entry in the cache
 // If the cache is full, it evicts the least recently used entries
 func (wc *WebCache) Put(url string, content []byte, contentType string, ttl time.Duration, statusCode int) error {
@@ -111,7 +165,7 @@ func (wc *WebCache) Put(url string, content []byte, contentType string, ttl time
 		oldest := wc.lru.Back()
 		if oldest != nil {
 			oldURL := oldest.Value.(string)
-			wc.evictEntry(oldURL)
+			wc.evictEntry(oldURL, "capacity")
 		}
 	}
 
@@ -136,8 +190,66 @@ func (wc *WebCache) Put(url string, content []byte, contentType string, ttl time
 	return nil
 }
 
-// evictEntry removes an entry from the cache (must be called with lock held)
-func (wc *WebCache) evictEntry(url string) {
+// PutValidated is Put plus the RFC 7234 validators/directives an origin
+// response carried, so a later stale hit can be conditionally revalidated
+// instead of refetched, and can honor a stale-while-revalidate window.
+func (wc *WebCache) PutValidated(url string, content []byte, contentType string, ttl time.Duration, statusCode int, etag, lastModified string, cc CacheControl) error {
+	if err := wc.Put(url, content, contentType, ttl, statusCode); err != nil {
+		return err
+	}
+
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	entry, ok := wc.entries[url]
+	if !ok {
+		return nil
+	}
+	entry.ETag = etag
+	entry.LastModified = lastModified
+	entry.CacheControl = cc
+	if cc.HasStaleWhileRevalidate {
+		entry.StaleUntil = entry.Expiration.Add(cc.StaleWhileRevalidate)
+	} else {
+		entry.StaleUntil = time.Time{}
+	}
+	return nil
+}
+
+// RefreshValidated extends url's freshness lifetime by ttl from now,
+// without touching its content, and updates its validators/directives if
+// the revalidation response carried new ones — the effect of a successful
+// 304 Not Modified response (RFC 7234 §4.3.3).
+func (wc *WebCache) RefreshValidated(url string, ttl time.Duration, etag, lastModified string, cc CacheControl) bool {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	entry, ok := wc.entries[url]
+	if !ok {
+		return false
+	}
+	if etag != "" {
+		entry.ETag = etag
+	}
+	if lastModified != "" {
+		entry.LastModified = lastModified
+	}
+	entry.CacheControl = cc
+	entry.Expiration = time.Now().Add(ttl)
+	if cc.HasStaleWhileRevalidate {
+		entry.StaleUntil = entry.Expiration.Add(cc.StaleWhileRevalidate)
+	} else {
+		entry.StaleUntil = time.Time{}
+	}
+	wc.lru.MoveToFront(entry.element)
+	return true
+}
+
+// evictEntry removes an entry from the cache (must be called with lock
+// held). reason classifies why, which also decides the CacheEvent kind
+// published to wc.events if one is set: "deleted" is an explicit
+// invalidation, anything else ("expired", "capacity") is housekeeping
+// eviction.
+func (wc *WebCache) evictEntry(url, reason string) {
 	entry, ok := wc.entries[url]
 	if !ok {
 		return
@@ -152,6 +264,20 @@ func (wc *WebCache) evictEntry(url string) {
 	delete(wc.entries, url)
 	wc.currentBytes -= entry.Size
 	wc.evictions++
+
+	if wc.events != nil {
+		kind := EventEvicted
+		if reason == "deleted" {
+			kind = EventInvalidated
+		}
+		wc.events.Publish(CacheEvent{
+			Type:      kind,
+			URL:       url,
+			Timestamp: time.Now(),
+			Reason:    reason,
+			Size:      entry.Size,
+		})
+	}
 }
 
 // Delete removes a specific entry (for cache invalidation)
@@ -160,7 +286,7 @@ func (wc *WebCache) Delete(url string) bool {
 	defer wc.mu.Unlock()
 
 	if _, ok := wc.entries[url]; ok {
-		wc.evictEntry(url)
+		wc.evictEntry(url, "deleted")
 		return true
 	}
 	return false
@@ -185,7 +311,7 @@ func (wc *WebCache) CleanExpired() int {
 
 	// Remove them
 	for _, url := range expired {
-		wc.evictEntry(url)
+		wc.evictEntry(url, "expired")
 		cleaned++
 	}
 
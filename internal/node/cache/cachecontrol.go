@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpDateFormat is the HTTP-date layout used by Expires/Last-Modified
+// (RFC 7231 §7.1.1.1), matching net/http.TimeFormat without importing
+// net/http into this transport-agnostic package.
+const httpDateFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+// CacheControl holds the subset of RFC 7234 Cache-Control directives this
+// cache understands, parsed once per origin response.
+type CacheControl struct {
+	NoStore        bool
+	NoCache        bool
+	Private        bool
+	MustRevalidate bool
+
+	MaxAge    time.Duration
+	HasMaxAge bool
+
+	SMaxAge    time.Duration
+	HasSMaxAge bool
+
+	StaleWhileRevalidate    time.Duration
+	HasStaleWhileRevalidate bool
+}
+
+// ParseCacheControl parses a Cache-Control header value into a
+// CacheControl. Unknown directives are ignored; a missing or empty header
+// parses to the zero value (no directives present).
+func ParseCacheControl(header string) CacheControl {
+	var cc CacheControl
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(part, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch name {
+		case "no-store":
+			cc.NoStore = true
+		case "no-cache":
+			cc.NoCache = true
+		case "private":
+			cc.Private = true
+		case "must-revalidate", "proxy-revalidate":
+			cc.MustRevalidate = true
+		case "max-age":
+			if secs, err := strconv.Atoi(value); err == nil {
+				cc.MaxAge = time.Duration(secs) * time.Second
+				cc.HasMaxAge = true
+			}
+		case "s-maxage":
+			if secs, err := strconv.Atoi(value); err == nil {
+				cc.SMaxAge = time.Duration(secs) * time.Second
+				cc.HasSMaxAge = true
+			}
+		case "stale-while-revalidate":
+			if secs, err := strconv.Atoi(value); err == nil {
+				cc.StaleWhileRevalidate = time.Duration(secs) * time.Second
+				cc.HasStaleWhileRevalidate = true
+			}
+		}
+	}
+
+	return cc
+}
+
+// TTL derives how long a response may be cached, preferring s-maxage over
+// max-age per RFC 7234 §5.2.2.9, falling back to the Expires header, and
+// finally to def if none of those are present or parseable.
+func (cc CacheControl) TTL(expiresHeader string, def time.Duration) time.Duration {
+	if cc.HasSMaxAge {
+		return cc.SMaxAge
+	}
+	if cc.HasMaxAge {
+		return cc.MaxAge
+	}
+	if expiresHeader != "" {
+		if t, err := time.Parse(httpDateFormat, expiresHeader); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return def
+}
+
+// Cacheable reports whether a response carrying this CacheControl may be
+// stored at all. no-store and private both forbid a shared cache like this
+// one from keeping a copy.
+func (cc CacheControl) Cacheable() bool {
+	return !cc.NoStore && !cc.Private
+}
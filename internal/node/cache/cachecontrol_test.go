@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   CacheControl
+	}{
+		{
+			name:   "empty header",
+			header: "",
+			want:   CacheControl{},
+		},
+		{
+			name:   "max-age",
+			header: "max-age=60",
+			want:   CacheControl{MaxAge: 60 * time.Second, HasMaxAge: true},
+		},
+		{
+			name:   "s-maxage takes precedence over max-age in TTL, both parsed",
+			header: "max-age=60, s-maxage=120",
+			want: CacheControl{
+				MaxAge: 60 * time.Second, HasMaxAge: true,
+				SMaxAge: 120 * time.Second, HasSMaxAge: true,
+			},
+		},
+		{
+			name:   "no-store and private",
+			header: "no-store, private",
+			want:   CacheControl{NoStore: true, Private: true},
+		},
+		{
+			name:   "must-revalidate and proxy-revalidate both set MustRevalidate",
+			header: "proxy-revalidate",
+			want:   CacheControl{MustRevalidate: true},
+		},
+		{
+			name:   "stale-while-revalidate",
+			header: "max-age=60, stale-while-revalidate=30",
+			want: CacheControl{
+				MaxAge: 60 * time.Second, HasMaxAge: true,
+				StaleWhileRevalidate: 30 * time.Second, HasStaleWhileRevalidate: true,
+			},
+		},
+		{
+			name:   "unknown directive ignored",
+			header: "max-age=60, community=UCI",
+			want:   CacheControl{MaxAge: 60 * time.Second, HasMaxAge: true},
+		},
+		{
+			name:   "unparseable max-age is dropped",
+			header: "max-age=not-a-number",
+			want:   CacheControl{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCacheControl(tt.header)
+			if got != tt.want {
+				t.Errorf("ParseCacheControl(%q) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlTTL(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(httpDateFormat)
+	past := time.Now().Add(-time.Hour).Format(httpDateFormat)
+
+	tests := []struct {
+		name          string
+		cc            CacheControl
+		expiresHeader string
+		def           time.Duration
+		want          time.Duration
+	}{
+		{
+			name: "s-maxage wins over max-age",
+			cc: CacheControl{
+				MaxAge: 10 * time.Second, HasMaxAge: true,
+				SMaxAge: 20 * time.Second, HasSMaxAge: true,
+			},
+			def:  5 * time.Second,
+			want: 20 * time.Second,
+		},
+		{
+			name: "max-age wins over Expires",
+			cc:   CacheControl{MaxAge: 10 * time.Second, HasMaxAge: true},
+			def:  5 * time.Second,
+			want: 10 * time.Second,
+		},
+		{
+			name:          "falls back to Expires when no max-age/s-maxage",
+			expiresHeader: future,
+			def:           5 * time.Second,
+			want:          time.Hour,
+		},
+		{
+			name:          "a past Expires means already-expired (zero TTL)",
+			expiresHeader: past,
+			def:           5 * time.Second,
+			want:          0,
+		},
+		{
+			name: "falls back to def with nothing else present",
+			def:  5 * time.Second,
+			want: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cc.TTL(tt.expiresHeader, tt.def)
+			// The Expires-header case computes time.Until at call time, so
+			// allow a little slack instead of requiring exact equality.
+			diff := got - tt.want
+			if diff < -time.Second || diff > time.Second {
+				t.Errorf("TTL() = %v, want ~%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlCacheable(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   CacheControl
+		want bool
+	}{
+		{name: "default is cacheable", cc: CacheControl{}, want: true},
+		{name: "no-store forbids caching", cc: CacheControl{NoStore: true}, want: false},
+		{name: "private forbids a shared cache", cc: CacheControl{Private: true}, want: false},
+		{name: "no-cache alone is still cacheable", cc: CacheControl{NoCache: true}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cc.Cacheable(); got != tt.want {
+				t.Errorf("Cacheable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPutValidatedAndRefreshValidated(t *testing.T) {
+	wc := NewWebCache(1)
+
+	cc := CacheControl{HasStaleWhileRevalidate: true, StaleWhileRevalidate: time.Minute}
+	if err := wc.PutValidated("http://example.com/a", []byte("body"), "text/plain", time.Second, 200,
+		`"etag-1"`, "Mon, 01 Jan 2024 00:00:00 GMT", cc); err != nil {
+		t.Fatalf("PutValidated() unexpected err: %v", err)
+	}
+
+	entry, ok := wc.Peek("http://example.com/a")
+	if !ok {
+		t.Fatalf("Peek() after PutValidated: not found")
+	}
+	if entry.ETag != `"etag-1"` {
+		t.Errorf("entry.ETag = %q, want %q", entry.ETag, `"etag-1"`)
+	}
+	if !entry.CacheControl.HasStaleWhileRevalidate {
+		t.Errorf("entry.CacheControl.HasStaleWhileRevalidate = false, want true")
+	}
+	if !entry.StaleUntil.After(entry.Expiration) {
+		t.Errorf("entry.StaleUntil = %v, want after Expiration %v", entry.StaleUntil, entry.Expiration)
+	}
+
+	// The Peek snapshot must not alias the live entry: mutating it must not
+	// affect what a later Peek/Get sees.
+	entry.ETag = "mutated"
+	if again, _ := wc.Peek("http://example.com/a"); again.ETag != `"etag-1"` {
+		t.Errorf("mutating a Peek snapshot leaked into the live entry: ETag = %q", again.ETag)
+	}
+
+	ccNoSWR := CacheControl{}
+	if ok := wc.RefreshValidated("http://example.com/a", 10*time.Second, `"etag-2"`, "", ccNoSWR); !ok {
+		t.Fatalf("RefreshValidated() = false, want true")
+	}
+	refreshed, _ := wc.Peek("http://example.com/a")
+	if refreshed.ETag != `"etag-2"` {
+		t.Errorf("refreshed.ETag = %q, want %q", refreshed.ETag, `"etag-2"`)
+	}
+	if !refreshed.StaleUntil.IsZero() {
+		t.Errorf("refreshed.StaleUntil = %v, want zero (no stale-while-revalidate this time)", refreshed.StaleUntil)
+	}
+}
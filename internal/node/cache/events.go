@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheEventKind classifies what happened to a URL in a CacheEvent.
+type CacheEventKind string
+
+const (
+	// EventInvalidated is published when a URL is removed by an explicit
+	// Delete call, as opposed to LRU/expiry housekeeping.
+	EventInvalidated CacheEventKind = "invalidated"
+	// EventEvicted is published when a URL is removed by LRU capacity
+	// pressure or because it expired (Get's lazy check or CleanExpired).
+	EventEvicted CacheEventKind = "evicted"
+	// EventPromoted is published when HotspotDetector first classifies a
+	// URL as hot, i.e. on the transition into hot rather than on every
+	// access while it stays hot.
+	EventPromoted CacheEventKind = "promoted"
+)
+
+// CacheEvent describes one cache-invalidation-relevant change to a URL, for
+// subscribers of an EventBus (see GET /cache/events).
+type CacheEvent struct {
+	Type      CacheEventKind `json:"type"`
+	URL       string         `json:"url"`
+	Timestamp time.Time      `json:"timestamp"`
+	Reason    string         `json:"reason"`
+
+	// Size is the entry's cached content size in bytes at the time of the
+	// event. It's always 0 for EventPromoted, since HotspotDetector tracks
+	// request rate, not content size.
+	Size int `json:"size"`
+}
+
+// defaultRingBufferSize bounds how many unconsumed events a single
+// subscriber may queue before Publish starts dropping its oldest ones.
+const defaultRingBufferSize = 256
+
+// defaultMaxMessageBytes matches the size HTTP/gRPC-websocket bridges have
+// historically capped a single message at, so a subscriber transport (see
+// server.handleCacheEvents) knows to frame one CacheEvent per message
+// rather than batching several into one.
+const defaultMaxMessageBytes = 64 * 1024
+
+// EventBus fans WebCache and HotspotDetector state changes out to
+// subscribers (e.g. GET /cache/events), each over its own bounded ring
+// buffer so one slow subscriber can't block another subscriber or the
+// cache write path that publishes. A full ring drops its oldest queued
+// event rather than the new one, and counts it in DroppedEvents.
+type EventBus struct {
+	ringBufferSize  int
+	maxMessageBytes int
+
+	mu            sync.Mutex
+	subs          map[*Subscription]struct{}
+	droppedEvents atomic.Uint64
+}
+
+// EventBusOption configures an EventBus constructed by NewEventBus.
+type EventBusOption func(*EventBus)
+
+// WithMaxMessageBytes overrides the per-event size budget reported by
+// MaxMessageBytes, in place of defaultMaxMessageBytes.
+func WithMaxMessageBytes(n int) EventBusOption {
+	return func(b *EventBus) { b.maxMessageBytes = n }
+}
+
+// WithRingBufferSize overrides how many unconsumed events a subscriber may
+// queue, in place of defaultRingBufferSize.
+func WithRingBufferSize(n int) EventBusOption {
+	return func(b *EventBus) { b.ringBufferSize = n }
+}
+
+// NewEventBus creates an empty EventBus.
+func NewEventBus(opts ...EventBusOption) *EventBus {
+	b := &EventBus{
+		ringBufferSize:  defaultRingBufferSize,
+		maxMessageBytes: defaultMaxMessageBytes,
+		subs:            make(map[*Subscription]struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// MaxMessageBytes is the per-event size budget a transport (SSE/WebSocket)
+// should honor when framing events, so a single frame never grows past
+// what historically tripped up HTTP/gRPC-websocket bridges.
+func (b *EventBus) MaxMessageBytes() int {
+	return b.maxMessageBytes
+}
+
+// DroppedEvents returns the cumulative number of events dropped across
+// every subscriber because its ring buffer was full when published.
+func (b *EventBus) DroppedEvents() uint64 {
+	return b.droppedEvents.Load()
+}
+
+// Subscribe registers a new subscriber whose Next only returns events
+// whose URL matches filterGlob (path.Match syntax; empty or "*" matches
+// everything). Callers must Unsubscribe when done.
+func (b *EventBus) Subscribe(filterGlob string) *Subscription {
+	sub := &Subscription{
+		filterGlob: filterGlob,
+		ring:       make([]CacheEvent, b.ringBufferSize),
+		signal:     make(chan struct{}, 1),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes a subscriber and wakes any goroutine blocked in its
+// Next so it can observe the subscription is closed.
+func (b *EventBus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subs, sub)
+	b.mu.Unlock()
+
+	sub.close()
+}
+
+// Publish fans e out to every subscriber whose filter matches it. A
+// subscriber whose ring buffer is already full has its oldest queued
+// event overwritten, and DroppedEvents is incremented — Publish itself
+// never blocks on a slow consumer.
+func (b *EventBus) Publish(e CacheEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.matches(e) {
+			continue
+		}
+		if sub.push(e) {
+			b.droppedEvents.Add(1)
+		}
+	}
+}
+
+// Subscription is one subscriber's bounded view of an EventBus, backed by
+// a fixed-size ring buffer that drops its oldest unconsumed event rather
+// than blocking Publish.
+type Subscription struct {
+	filterGlob string
+
+	mu     sync.Mutex
+	ring   []CacheEvent
+	head   int
+	count  int
+	closed bool
+	signal chan struct{}
+}
+
+// matches reports whether e's URL satisfies this subscription's filter.
+func (s *Subscription) matches(e CacheEvent) bool {
+	if s.filterGlob == "" || s.filterGlob == "*" {
+		return true
+	}
+	ok, err := path.Match(s.filterGlob, e.URL)
+	return err == nil && ok
+}
+
+// push enqueues e, reporting true if doing so overwrote an unconsumed
+// event because the ring was already full.
+func (s *Subscription) push(e CacheEvent) (dropped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	if s.count == len(s.ring) {
+		s.head = (s.head + 1) % len(s.ring)
+		dropped = true
+	} else {
+		s.count++
+	}
+	s.ring[(s.head+s.count-1)%len(s.ring)] = e
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
+// Next blocks until an event is available, ctx is done, or the
+// subscription is closed (via EventBus.Unsubscribe), whichever comes
+// first. ok is false in the latter two cases.
+func (s *Subscription) Next(ctx context.Context) (e CacheEvent, ok bool) {
+	for {
+		s.mu.Lock()
+		if s.count > 0 {
+			e = s.ring[s.head]
+			s.head = (s.head + 1) % len(s.ring)
+			s.count--
+			s.mu.Unlock()
+			return e, true
+		}
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return CacheEvent{}, false
+		}
+
+		select {
+		case <-s.signal:
+		case <-ctx.Done():
+			return CacheEvent{}, false
+		}
+	}
+}
+
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
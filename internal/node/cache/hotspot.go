@@ -1,29 +1,134 @@
 package cache
 
 import (
+	"container/heap"
 	"math"
 	"sync"
 	"time"
 )
 
-// HotspotDetector tracks request frequencies using exponential decay
-// to identify "hot" URLs that should be distributed across the cluster
+// DefaultHotspotCapacity bounds how many distinct URLs HotspotDetector
+// tracks at once, trading Space-Saving's bounded overestimation for O(1)
+// memory regardless of how many distinct URLs a node sees — the same
+// bounded-memory trade dht.HotKeyTracker makes for the DHT's own top-K
+// tracking.
+const DefaultHotspotCapacity = 4096
+
+// HotspotDetector tracks request frequencies using exponential decay, over
+// a fixed-capacity Space-Saving / Misra-Gries sketch, to identify "hot"
+// URLs that should be distributed across the cluster without letting an
+// adversarial or long-tail traffic pattern grow its memory unbounded.
+//
+// Internally, capacity URLs are held in a map keyed by URL plus a
+// min-heap ordered by decayed average, so the current minimum (the next
+// eviction candidate) is always O(1) to find. When a new URL arrives and
+// the sketch is full, the minimum slot is evicted and reused: the new
+// URL's average starts from the evicted slot's (decayed) average plus
+// one, and the evicted slot's decayed average (without that extra one) is
+// recorded as the slot's error — the
+// standard Space-Saving guarantee that average-error is a true lower
+// bound on the URL's actual count, even though it may have never been
+// observed before occupying this slot.
 type HotspotDetector struct {
 	threshold float64 // Requests/second threshold for hotspot classification
 	decayRate float64 // Exponential decay factor γ (typically 0.6-0.8)
+	capacity  int
+
+	mu    sync.RWMutex
+	slots map[string]*hotspotSlot
+	heap  hotspotHeap
+
+	// events receives a promoted CacheEvent the first time a URL crosses
+	// threshold, if set via SetEventBus. nil by default.
+	events *EventBus
+
+	// broadcaster receives a HotspotEvent on every hot/cool transition, if
+	// set via SetBroadcaster. nil by default.
+	broadcaster *HotspotBroadcaster
+
+	// onHot and onCool are invoked exactly once per hot/cool transition
+	// (see checkTransition), if set via OnHot/OnCool. nil by default.
+	onHot  func(url string, avg float64)
+	onCool func(url string, avg float64)
+}
 
-	entries map[string]*HotspotEntry
-	mu      sync.RWMutex
+// hotspotCoolFactor is the hysteresis margin below threshold a hot URL
+// must decay past before OnCool fires, so a URL whose rate is merely
+// oscillating around threshold doesn't flap between the two callbacks on
+// every other request.
+const hotspotCoolFactor = 0.5
+
+// OnHot registers cb to be called exactly once each time a URL's decayed
+// average crosses threshold upward — i.e. on the transition into "hot",
+// not on every subsequent access while it stays hot. Passing nil disables
+// the callback. Must be called before traffic starts, since it isn't
+// safe to call concurrently with RecordAccess.
+func (hd *HotspotDetector) OnHot(cb func(url string, avg float64)) {
+	hd.onHot = cb
+}
+
+// OnCool registers cb to be called exactly once each time a previously
+// hot URL's decayed average falls below threshold·hotspotCoolFactor —
+// i.e. with enough hysteresis margin that a rate oscillating around
+// threshold doesn't repeatedly fire OnHot/OnCool. Passing nil disables
+// the callback. Must be called before traffic starts, since it isn't
+// safe to call concurrently with RecordAccess.
+func (hd *HotspotDetector) OnCool(cb func(url string, avg float64)) {
+	hd.onCool = cb
 }
 
-// HotspotEntry tracks the decayed average request rate for a URL
-type HotspotEntry struct {
-	Average         float64 // Exponentially decayed average: H_t = γ·H_{t-1} + N_t
-	LastRequestTime int64   // Unix timestamp of last request (seconds)
-	TotalRequests   int64   // Total requests seen (for debugging)
+// hotspotSlot is one occupied slot in the sketch.
+type hotspotSlot struct {
+	url             string
+	average         float64 // Exponentially decayed average: H_t = γ^Δt·H_{t-1} + 1
+	err             float64 // Space-Saving overestimation bound recorded when this slot was last reassigned to url
+	lastRequestTime int64   // Unix timestamp of last request (seconds)
+	hot             bool    // whether url was classified hot as of the last RecordAccess, so a repeat hot access isn't re-published
+	index           int     // position in the heap, maintained by hotspotHeap
+}
+
+// hotspotHeap is a container/heap min-heap over hotspotSlot.average, so
+// the slot with the smallest (and therefore next-to-evict) count is
+// always heap[0].
+type hotspotHeap []*hotspotSlot
+
+func (h hotspotHeap) Len() int            { return len(h) }
+func (h hotspotHeap) Less(i, j int) bool  { return h[i].average < h[j].average }
+func (h hotspotHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i]; h[i].index = i; h[j].index = j }
+func (h *hotspotHeap) Push(x interface{}) {
+	s := x.(*hotspotSlot)
+	s.index = len(*h)
+	*h = append(*h, s)
+}
+func (h *hotspotHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	s := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return s
 }
 
-// NewHotspotDetector creates a new detector with the given parameters
+// SetEventBus installs the EventBus that RecordAccess publishes a
+// "promoted" event to the first time a URL becomes hot. Passing nil (the
+// default) disables publishing.
+func (hd *HotspotDetector) SetEventBus(bus *EventBus) {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
+	hd.events = bus
+}
+
+// SetBroadcaster installs the HotspotBroadcaster that checkTransition
+// publishes a HotspotEvent to on every hot/cool transition. Passing nil
+// (the default) disables publishing.
+func (hd *HotspotDetector) SetBroadcaster(b *HotspotBroadcaster) {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
+	hd.broadcaster = b
+}
+
+// NewHotspotDetector creates a new detector with the given parameters,
+// tracking up to DefaultHotspotCapacity distinct URLs at once.
 //
 // Recommended values:
 //   - threshold: 1000 (URLs with >1000 req/sec are considered hot)
@@ -32,72 +137,136 @@ func NewHotspotDetector(threshold, decayRate float64) *HotspotDetector {
 	return &HotspotDetector{
 		threshold: threshold,
 		decayRate: decayRate,
-		entries:   make(map[string]*HotspotEntry),
+		capacity:  DefaultHotspotCapacity,
+		slots:     make(map[string]*hotspotSlot, DefaultHotspotCapacity),
 	}
 }
 
+// decay applies exponential decay for the seconds elapsed since s was last
+// updated, and returns the result without mutating s.
+func (hd *HotspotDetector) decay(s *hotspotSlot, now int64) float64 {
+	secondsElapsed := float64(now - s.lastRequestTime)
+	return s.average * math.Pow(hd.decayRate, secondsElapsed)
+}
+
 // RecordAccess records a request for the given URL and updates its hotness score
 //
 // Returns true if the URL is now classified as "hot" (above threshold)
 //
-// Algorithm: Exponential Moving Average
+// Algorithm: Exponential Moving Average, Space-Saving eviction
 //   If same second:    H_t = H_{t-1} + 1
 //   If different second: H_t = γ^Δt · H_{t-1} + 1
 //   where Δt = seconds since last request
+//
+// If url isn't already tracked and the sketch is at capacity, the
+// current minimum-count slot is reassigned to url (see HotspotDetector's
+// doc comment for the Space-Saving accounting this applies).
 func (hd *HotspotDetector) RecordAccess(url string) bool {
 	hd.mu.Lock()
 	defer hd.mu.Unlock()
 
 	now := time.Now().Unix()
 
-	entry, exists := hd.entries[url]
-	if !exists {
-		// First request for this URL
-		hd.entries[url] = &HotspotEntry{
-			Average:         1.0,
-			LastRequestTime: now,
-			TotalRequests:   1,
+	if s, exists := hd.slots[url]; exists {
+		if s.lastRequestTime == now {
+			s.average += 1.0
+		} else {
+			s.average = hd.decay(s, now) + 1.0
+			s.lastRequestTime = now
 		}
+		heap.Fix(&hd.heap, s.index)
+		return hd.checkTransition(s)
+	}
+
+	if len(hd.slots) < hd.capacity {
+		s := &hotspotSlot{url: url, average: 1.0, lastRequestTime: now}
+		heap.Push(&hd.heap, s)
+		hd.slots[url] = s
 		return false // Can't be hot on first request
 	}
 
-	entry.TotalRequests++
-
-	if entry.LastRequestTime == now {
-		// Same second - just increment
-		entry.Average += 1.0
-	} else {
-		// Different second - apply exponential decay
-		secondsElapsed := float64(now - entry.LastRequestTime)
-		decayFactor := math.Pow(hd.decayRate, secondsElapsed)
-		entry.Average = entry.Average*decayFactor + 1.0
-		entry.LastRequestTime = now
+	// At capacity: evict the current minimum, Space-Saving style.
+	min := hd.heap[0]
+	delete(hd.slots, min.url)
+
+	decayedMin := hd.decay(min, now)
+	min.url = url
+	min.average = decayedMin + 1.0
+	min.err = decayedMin
+	min.lastRequestTime = now
+	min.hot = false
+	hd.slots[url] = min
+	heap.Fix(&hd.heap, min.index)
+
+	return hd.checkTransition(min)
+}
+
+// checkTransition fires the hot/cool edge-triggered notifications (the
+// "promoted" CacheEvent and OnHot/OnCool) when s's average crosses
+// threshold upward, or falls back below threshold·hotspotCoolFactor
+// having previously been hot, and returns whether s is currently hot
+// (without the cool-down hysteresis — "isHot" here means "at or above
+// threshold right now", matching RecordAccess's documented return value).
+// Must be called with hd.mu held.
+func (hd *HotspotDetector) checkTransition(s *hotspotSlot) bool {
+	isHot := s.average >= hd.threshold
+
+	switch {
+	case isHot && !s.hot:
+		if hd.events != nil {
+			hd.events.Publish(CacheEvent{
+				Type:      EventPromoted,
+				URL:       s.url,
+				Timestamp: time.Now(),
+				Reason:    "threshold_exceeded",
+			})
+		}
+		hd.publishTransition(HotspotEventHot, s)
+		if hd.onHot != nil {
+			hd.onHot(s.url, s.average)
+		}
+		s.hot = true
+	case s.hot && s.average < hd.threshold*hotspotCoolFactor:
+		hd.publishTransition(HotspotEventCool, s)
+		if hd.onCool != nil {
+			hd.onCool(s.url, s.average)
+		}
+		s.hot = false
 	}
 
-	// Check if now hot
-	isHot := entry.Average >= hd.threshold
 	return isHot
 }
 
+// publishTransition sends a HotspotEvent for s's hot/cool transition to
+// hd.broadcaster, if one is installed. Must be called with hd.mu held.
+func (hd *HotspotDetector) publishTransition(kind HotspotEventKind, s *hotspotSlot) {
+	if hd.broadcaster == nil {
+		return
+	}
+	hd.broadcaster.Publish(HotspotEvent{
+		Type:          kind,
+		URL:           s.url,
+		Average:       s.average,
+		TotalRequests: s.average - s.err,
+		Timestamp:     time.Now(),
+	})
+}
+
 // IsHot checks if a URL is currently classified as hot
 func (hd *HotspotDetector) IsHot(url string) bool {
 	hd.mu.RLock()
 	defer hd.mu.RUnlock()
 
-	entry, exists := hd.entries[url]
+	s, exists := hd.slots[url]
 	if !exists {
 		return false
 	}
-
-	// Apply decay based on time since last access
-	now := time.Now().Unix()
-	secondsElapsed := float64(now - entry.LastRequestTime)
-	decayedAverage := entry.Average * math.Pow(hd.decayRate, secondsElapsed)
-
-	return decayedAverage >= hd.threshold
+	return hd.decay(s, time.Now().Unix()) >= hd.threshold
 }
 
-// GetHotURLs returns a list of currently hot URLs
+// GetHotURLs returns a list of currently hot URLs, decaying each tracked
+// slot lazily rather than eagerly walking anything beyond the sketch's
+// bounded capacity.
 func (hd *HotspotDetector) GetHotURLs() []string {
 	hd.mu.RLock()
 	defer hd.mu.RUnlock()
@@ -105,64 +274,95 @@ func (hd *HotspotDetector) GetHotURLs() []string {
 	now := time.Now().Unix()
 	hotURLs := make([]string, 0)
 
-	for url, entry := range hd.entries {
-		secondsElapsed := float64(now - entry.LastRequestTime)
-		decayedAverage := entry.Average * math.Pow(hd.decayRate, secondsElapsed)
-
-		if decayedAverage >= hd.threshold {
-			hotURLs = append(hotURLs, url)
+	for _, s := range hd.heap {
+		if hd.decay(s, now) >= hd.threshold {
+			hotURLs = append(hotURLs, s.url)
 		}
 	}
 
 	return hotURLs
 }
 
-// GetStats returns statistics for a specific URL
-func (hd *HotspotDetector) GetStats(url string) (average float64, total int64, isHot bool) {
+// GetStats returns statistics for a specific URL: its current decayed
+// average, a guaranteed lower bound on its true count (average minus the
+// Space-Saving error recorded when this slot was last assigned to url),
+// and whether it's currently hot. A URL the sketch isn't tracking (either
+// never seen, or evicted in favor of something hotter) reports all
+// zeros/false.
+func (hd *HotspotDetector) GetStats(url string) (average float64, lowerBound float64, isHot bool) {
 	hd.mu.RLock()
 	defer hd.mu.RUnlock()
 
-	entry, exists := hd.entries[url]
+	s, exists := hd.slots[url]
 	if !exists {
 		return 0, 0, false
 	}
 
-	// Apply decay
-	now := time.Now().Unix()
-	secondsElapsed := float64(now - entry.LastRequestTime)
-	decayedAverage := entry.Average * math.Pow(hd.decayRate, secondsElapsed)
+	decayedAverage := hd.decay(s, time.Now().Unix())
+	return decayedAverage, decayedAverage - s.err, decayedAverage >= hd.threshold
+}
+
+// SweepCooling materializes decay for every currently-hot slot and fires
+// OnCool for any that have fallen below threshold·hotspotCoolFactor since
+// their last access. RecordAccess only re-checks a URL's own transitions
+// when that URL itself is accessed again, so a hot URL whose traffic
+// simply stops would otherwise never fire OnCool — callers should invoke
+// SweepCooling periodically (e.g. alongside CleanStale) to catch that
+// case. Returns how many slots cooled down.
+func (hd *HotspotDetector) SweepCooling() int {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
 
-	return decayedAverage, entry.TotalRequests, decayedAverage >= hd.threshold
+	now := time.Now().Unix()
+	cooled := 0
+	for _, s := range hd.heap {
+		if !s.hot {
+			continue
+		}
+		s.average = hd.decay(s, now)
+		s.lastRequestTime = now
+		heap.Fix(&hd.heap, s.index)
+		if s.average < hd.threshold*hotspotCoolFactor {
+			hd.publishTransition(HotspotEventCool, s)
+			if hd.onCool != nil {
+				hd.onCool(s.url, s.average)
+			}
+			s.hot = false
+			cooled++
+		}
+	}
+	return cooled
 }
 
 // Clear removes all tracked URLs
 func (hd *HotspotDetector) Clear() {
 	hd.mu.Lock()
 	defer hd.mu.Unlock()
-	hd.entries = make(map[string]*HotspotEntry)
+	hd.slots = make(map[string]*hotspotSlot, hd.capacity)
+	hd.heap = nil
 }
 
-// CleanStale removes entries that haven't been accessed recently
-// Called periodically to prevent unbounded memory growth
+// CleanStale removes slots that haven't been accessed recently. With a
+// bounded sketch this is no longer needed to prevent unbounded growth —
+// Space-Saving eviction already guarantees that — but it still frees
+// slots for new traffic sooner than waiting for them to be Space-Saving
+// evicted one at a time.
 func (hd *HotspotDetector) CleanStale(maxAge time.Duration) int {
 	hd.mu.Lock()
 	defer hd.mu.Unlock()
 
 	now := time.Now().Unix()
-	cleaned := 0
-	staleURLs := make([]string, 0)
-
-	for url, entry := range hd.entries {
-		age := now - entry.LastRequestTime
-		if time.Duration(age)*time.Second > maxAge {
-			staleURLs = append(staleURLs, url)
+	var stale []*hotspotSlot
+	for _, s := range hd.heap {
+		if time.Duration(now-s.lastRequestTime)*time.Second > maxAge {
+			stale = append(stale, s)
 		}
 	}
 
-	for _, url := range staleURLs {
-		delete(hd.entries, url)
-		cleaned++
+	for _, s := range stale {
+		delete(hd.slots, s.url)
+		heap.Remove(&hd.heap, s.index)
 	}
 
-	return cleaned
-}
\ No newline at end of file
+	return len(stale)
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// HotspotEventKind classifies a HotspotEvent.
+type HotspotEventKind string
+
+const (
+	// HotspotEventHot is published the first time a URL's decayed average
+	// crosses threshold upward (see HotspotDetector.checkTransition).
+	HotspotEventHot HotspotEventKind = "hot"
+	// HotspotEventCool is published when a previously hot URL's decayed
+	// average falls back below threshold·hotspotCoolFactor.
+	HotspotEventCool HotspotEventKind = "cool"
+)
+
+// HotspotEvent describes one hot/cool transition, for subscribers of a
+// HotspotBroadcaster (see GET /hotspots/stream).
+type HotspotEvent struct {
+	Type HotspotEventKind `json:"type"`
+	URL  string           `json:"url"`
+
+	// Average is the URL's decayed request-rate average at the moment of
+	// the transition.
+	Average float64 `json:"average"`
+
+	// TotalRequests is a guaranteed lower bound on the URL's true request
+	// count (Average minus the Space-Saving error recorded when its slot
+	// was last assigned to it — see HotspotDetector.GetStats).
+	TotalRequests float64   `json:"total_requests"`
+	Timestamp     time.Time `json:"ts"`
+}
+
+// defaultHotspotSubscriberQueueSize bounds how many unconsumed events a
+// single hotspot stream subscriber may queue before Publish starts
+// dropping its oldest one, so one slow SSE client can't block another or
+// the RecordAccess path that publishes.
+const defaultHotspotSubscriberQueueSize = 64
+
+// HotspotBroadcaster fans HotspotEvents out to subscribers (e.g. GET
+// /hotspots/stream), each over its own bounded, drop-oldest channel —
+// unlike EventBus's ring buffer, a subscriber here is just the channel
+// itself, since a hotspot stream client only ever wants "what's hot or
+// cooling right now".
+type HotspotBroadcaster struct {
+	mu   sync.Mutex
+	subs map[chan HotspotEvent]struct{}
+}
+
+// NewHotspotBroadcaster creates an empty HotspotBroadcaster.
+func NewHotspotBroadcaster() *HotspotBroadcaster {
+	return &HotspotBroadcaster{subs: make(map[chan HotspotEvent]struct{})}
+}
+
+// Subscribe registers a new subscriber channel. Callers must Unsubscribe
+// when done to stop Publish from writing to it.
+func (b *HotspotBroadcaster) Subscribe() chan HotspotEvent {
+	ch := make(chan HotspotEvent, defaultHotspotSubscriberQueueSize)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch
+}
+
+// Unsubscribe removes ch so Publish stops writing to it.
+func (b *HotspotBroadcaster) Unsubscribe(ch chan HotspotEvent) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+// Publish fans e out to every subscriber. A subscriber whose queue is
+// already full has its oldest queued event dropped to make room, rather
+// than blocking Publish on a slow consumer.
+func (b *HotspotBroadcaster) Publish(e HotspotEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+			continue
+		default:
+		}
+
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+// snapshotMagic identifies a WebCache snapshot; snapshotVersion lets
+// Restore refuse a framing it doesn't understand instead of misreading one
+// written by some future, incompatible format.
+var snapshotMagic = [4]byte{'K', 'D', 'W', 'C'}
+
+// snapshotVersion 2 added the RFC 7234 validator/directive fields
+// (ETag, LastModified, CacheControl, StaleUntil) that PutValidated/
+// RefreshValidated attach to an Entry; version 1 snapshots predate those
+// fields and are refused rather than silently restored without them.
+const snapshotVersion = 2
+
+// crc32cTable is the Castagnoli polynomial, matching the conventional
+// meaning of "CRC32C" (as opposed to the slower IEEE polynomial).
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Snapshot writes every entry currently in the cache to w, in a versioned
+// framed format: magic bytes, version, a record count, one length-prefixed
+// record per entry (most-recently-used first, preserving LRU order), and a
+// trailing CRC32C over everything written before it. Restore reads this
+// format back.
+func (wc *WebCache) Snapshot(w io.Writer) error {
+	wc.mu.RLock()
+	defer wc.mu.RUnlock()
+
+	cw := &checksummingWriter{w: w}
+
+	if err := binary.Write(cw, binary.BigEndian, snapshotMagic); err != nil {
+		return fmt.Errorf("cache: snapshot: write magic: %w", err)
+	}
+	if err := binary.Write(cw, binary.BigEndian, uint32(snapshotVersion)); err != nil {
+		return fmt.Errorf("cache: snapshot: write version: %w", err)
+	}
+	if err := binary.Write(cw, binary.BigEndian, uint32(wc.lru.Len())); err != nil {
+		return fmt.Errorf("cache: snapshot: write record count: %w", err)
+	}
+
+	for e := wc.lru.Front(); e != nil; e = e.Next() {
+		url := e.Value.(string)
+		entry, ok := wc.entries[url]
+		if !ok {
+			continue
+		}
+		if err := writeSnapshotRecord(cw, entry); err != nil {
+			return fmt.Errorf("cache: snapshot: write record for %q: %w", url, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.BigEndian, cw.sum); err != nil {
+		return fmt.Errorf("cache: snapshot: write checksum: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the cache's contents with the entries read from r, a
+// snapshot written by Snapshot. Already-expired records are skipped. If the
+// persisted total exceeds capacityBytes, the oldest (least-recently-used)
+// records are dropped first, since records are stored and read back in
+// most-recently-used-first order. The trailing checksum is verified before
+// any record is read, so a truncated or corrupted snapshot is rejected
+// atomically instead of leaving the cache partially repopulated.
+func (wc *WebCache) Restore(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("cache: restore: read snapshot: %w", err)
+	}
+	if len(data) < 4 {
+		return fmt.Errorf("cache: restore: snapshot too short")
+	}
+
+	body, wantSum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if gotSum := crc32.Checksum(body, crc32cTable); gotSum != wantSum {
+		return fmt.Errorf("cache: restore: checksum mismatch, snapshot is corrupt")
+	}
+
+	br := bytes.NewReader(body)
+
+	var magic [4]byte
+	if err := binary.Read(br, binary.BigEndian, &magic); err != nil {
+		return fmt.Errorf("cache: restore: read magic: %w", err)
+	}
+	if magic != snapshotMagic {
+		return fmt.Errorf("cache: restore: not a WebCache snapshot")
+	}
+
+	var version uint32
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return fmt.Errorf("cache: restore: read version: %w", err)
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("cache: restore: unsupported snapshot version %d", version)
+	}
+
+	var count uint32
+	if err := binary.Read(br, binary.BigEndian, &count); err != nil {
+		return fmt.Errorf("cache: restore: read record count: %w", err)
+	}
+
+	now := time.Now()
+	entries := make([]*Entry, 0, count)
+	for i := uint32(0); i < count; i++ {
+		entry, err := readSnapshotRecord(br)
+		if err != nil {
+			return fmt.Errorf("cache: restore: read record %d: %w", i, err)
+		}
+		if now.After(entry.Expiration) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	wc.entries = make(map[string]*Entry, len(entries))
+	wc.lru = list.New()
+	wc.currentBytes = 0
+
+	for _, entry := range entries {
+		if wc.currentBytes+entry.Size > wc.capacityBytes {
+			// Every later entry is older (less recently used) than this
+			// one, so stopping here drops exactly the oldest entries.
+			break
+		}
+		entry.element = wc.lru.PushBack(entry.URL)
+		wc.entries[entry.URL] = entry
+		wc.currentBytes += entry.Size
+	}
+
+	return nil
+}
+
+// SnapshotToFile writes a Snapshot to path via a temp-file-then-rename, so
+// a crash or error mid-write never leaves a truncated snapshot at path.
+func (wc *WebCache) SnapshotToFile(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("cache: snapshot: create %s: %w", tmp, err)
+	}
+
+	bw := bufio.NewWriter(f)
+	if err := wc.Snapshot(bw); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("cache: snapshot: flush %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("cache: snapshot: close %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("cache: snapshot: rename %s to %s: %w", tmp, path, err)
+	}
+	return nil
+}
+
+// RestoreFromFile loads a snapshot written by SnapshotToFile. A missing
+// file is not an error: a node's first run, or one with snapshotting
+// disabled, simply has nothing to restore yet.
+func (wc *WebCache) RestoreFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("cache: restore: open %s: %w", path, err)
+	}
+	defer f.Close()
+	return wc.Restore(f)
+}
+
+// checksummingWriter wraps an io.Writer, accumulating a running CRC32C of
+// everything written through it, so Snapshot can append the checksum after
+// writing the framed body without buffering it all in memory first.
+type checksummingWriter struct {
+	w   io.Writer
+	sum uint32
+}
+
+func (cw *checksummingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if n > 0 {
+		cw.sum = crc32.Update(cw.sum, crc32cTable, p[:n])
+	}
+	return n, err
+}
+
+func writeSnapshotRecord(w io.Writer, entry *Entry) error {
+	if err := writeSnapshotBytes(w, []byte(entry.URL)); err != nil {
+		return err
+	}
+	if err := writeSnapshotBytes(w, []byte(entry.ContentType)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, int32(entry.StatusCode)); err != nil {
+		return err
+	}
+	if err := writeSnapshotBytes(w, entry.Content); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, entry.Expiration.UnixNano()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, entry.CreatedAt.UnixNano()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, entry.AccessCount); err != nil {
+		return err
+	}
+	return writeSnapshotValidators(w, entry)
+}
+
+// writeSnapshotValidators writes entry's RFC 7234 validators/directives
+// (ETag, LastModified, CacheControl, StaleUntil), added in snapshotVersion
+// 2 so a restored entry can still do a cheap conditional revalidation and
+// honor its origin's stale-while-revalidate window instead of falling
+// through to a full re-fetch on every restart.
+func writeSnapshotValidators(w io.Writer, entry *Entry) error {
+	if err := writeSnapshotBytes(w, []byte(entry.ETag)); err != nil {
+		return err
+	}
+	if err := writeSnapshotBytes(w, []byte(entry.LastModified)); err != nil {
+		return err
+	}
+
+	cc := entry.CacheControl
+	var flags byte
+	if cc.NoStore {
+		flags |= 1 << 0
+	}
+	if cc.NoCache {
+		flags |= 1 << 1
+	}
+	if cc.Private {
+		flags |= 1 << 2
+	}
+	if cc.MustRevalidate {
+		flags |= 1 << 3
+	}
+	if cc.HasMaxAge {
+		flags |= 1 << 4
+	}
+	if cc.HasSMaxAge {
+		flags |= 1 << 5
+	}
+	if cc.HasStaleWhileRevalidate {
+		flags |= 1 << 6
+	}
+	if err := binary.Write(w, binary.BigEndian, flags); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, int64(cc.MaxAge)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, int64(cc.SMaxAge)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, int64(cc.StaleWhileRevalidate)); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.BigEndian, entry.StaleUntil.UnixNano())
+}
+
+func readSnapshotRecord(r io.Reader) (*Entry, error) {
+	url, err := readSnapshotString(r)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := readSnapshotString(r)
+	if err != nil {
+		return nil, err
+	}
+	var statusCode int32
+	if err := binary.Read(r, binary.BigEndian, &statusCode); err != nil {
+		return nil, err
+	}
+	content, err := readSnapshotBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	var expirationNanos, createdAtNanos int64
+	if err := binary.Read(r, binary.BigEndian, &expirationNanos); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &createdAtNanos); err != nil {
+		return nil, err
+	}
+	var accessCount int64
+	if err := binary.Read(r, binary.BigEndian, &accessCount); err != nil {
+		return nil, err
+	}
+
+	etag, lastModified, cc, staleUntil, err := readSnapshotValidators(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		URL:          url,
+		ContentType:  contentType,
+		StatusCode:   int(statusCode),
+		Content:      content,
+		Size:         len(content),
+		Expiration:   time.Unix(0, expirationNanos),
+		CreatedAt:    time.Unix(0, createdAtNanos),
+		AccessCount:  accessCount,
+		ETag:         etag,
+		LastModified: lastModified,
+		CacheControl: cc,
+		StaleUntil:   staleUntil,
+	}, nil
+}
+
+// readSnapshotValidators reads the fields written by writeSnapshotValidators.
+func readSnapshotValidators(r io.Reader) (etag, lastModified string, cc CacheControl, staleUntil time.Time, err error) {
+	if etag, err = readSnapshotString(r); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	if lastModified, err = readSnapshotString(r); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+
+	var flags byte
+	if err = binary.Read(r, binary.BigEndian, &flags); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	cc.NoStore = flags&(1<<0) != 0
+	cc.NoCache = flags&(1<<1) != 0
+	cc.Private = flags&(1<<2) != 0
+	cc.MustRevalidate = flags&(1<<3) != 0
+	cc.HasMaxAge = flags&(1<<4) != 0
+	cc.HasSMaxAge = flags&(1<<5) != 0
+	cc.HasStaleWhileRevalidate = flags&(1<<6) != 0
+
+	var maxAge, sMaxAge, staleWhileRevalidate, staleUntilNanos int64
+	if err = binary.Read(r, binary.BigEndian, &maxAge); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	if err = binary.Read(r, binary.BigEndian, &sMaxAge); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	if err = binary.Read(r, binary.BigEndian, &staleWhileRevalidate); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	if err = binary.Read(r, binary.BigEndian, &staleUntilNanos); err != nil {
+		return "", "", CacheControl{}, time.Time{}, err
+	}
+	cc.MaxAge = time.Duration(maxAge)
+	cc.SMaxAge = time.Duration(sMaxAge)
+	cc.StaleWhileRevalidate = time.Duration(staleWhileRevalidate)
+	if cc.HasStaleWhileRevalidate {
+		staleUntil = time.Unix(0, staleUntilNanos)
+	}
+
+	return etag, lastModified, cc, staleUntil, nil
+}
+
+func writeSnapshotBytes(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readSnapshotString(r io.Reader) (string, error) {
+	b, err := readSnapshotBytes(r)
+	return string(b), err
+}
+
+func readSnapshotBytes(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
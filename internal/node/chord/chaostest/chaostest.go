@@ -0,0 +1,45 @@
+// Package chaostest lets integration tests inject network faults into a
+// running Chord ring: dropped, delayed, or partitioned traffic between
+// selected peers, on the exact RPC paths stabilize, LookUp (and so
+// fixFinger), and checkPredecessor use. See Harness for spinning up a
+// ring of in-process nodes, and FaultyDialer for the underlying
+// fault-injecting chord.PoolDialer.
+package chaostest
+
+import (
+	"KoordeDHT/internal/node/chord"
+	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/testfaults"
+	"fmt"
+	"time"
+)
+
+// FaultyDialer wraps a real chord.PoolDialer, consulting a shared
+// testfaults.Controller before every dial so a test can drop, delay, or
+// partition traffic between self and any peer without the Chord
+// implementation itself knowing fault injection is happening.
+type FaultyDialer struct {
+	real       chord.PoolDialer
+	controller *testfaults.Controller
+	self       string
+}
+
+// NewFaultyDialer returns a FaultyDialer that dials through real on
+// success, consulting controller for every (self, addr) pair first.
+func NewFaultyDialer(real chord.PoolDialer, controller *testfaults.Controller, self string) *FaultyDialer {
+	return &FaultyDialer{real: real, controller: controller, self: self}
+}
+
+// GetFromPool applies the fault plan registered for (self, addr), if any,
+// then either returns a dial failure or delays and forwards to the
+// wrapped dialer.
+func (d *FaultyDialer) GetFromPool(addr string) (*client2.Client, error) {
+	drop, delay := d.controller.Evaluate(d.self, addr)
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if drop {
+		return nil, fmt.Errorf("chaostest: injected fault dropped dial from %s to %s", d.self, addr)
+	}
+	return d.real.GetFromPool(addr)
+}
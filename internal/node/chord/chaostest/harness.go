@@ -0,0 +1,193 @@
+package chaostest
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/chord"
+	client2 "KoordeDHT/internal/node/client"
+	server2 "KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/testfaults"
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// defaultFailureTimeout bounds how long a harness node waits on a single
+// RPC before giving up, matching the per-hop budgets chord.Node itself
+// uses elsewhere.
+const defaultFailureTimeout = 2 * time.Second
+
+// Harness runs n chord.Node instances in-process, each reachable over a
+// real loopback gRPC listener, with every stabilize/LookUp/checkPredecessor
+// dial routed through a FaultyDialer sharing one testfaults.Controller.
+// Scenario helpers (KillPredecessor, PartitionSuccessorList, SlowNotify)
+// manipulate that Controller directly; AssertConverged checks the ring
+// heals afterward.
+type Harness struct {
+	Controller *testfaults.Controller
+
+	space   domain.Space
+	lgr     logger.Logger
+	nodes   []*chord.Node
+	servers []*server2.Server
+	// cps holds every node's real (unfaulted) client pool, so
+	// AssertConverged can query the ring's true state directly instead of
+	// through a FaultyDialer.
+	cps    []*client2.Pool
+	cancel context.CancelFunc
+}
+
+// NewHarness starts n Chord nodes on consecutive loopback ports starting
+// at basePort, joins them into a single ring (node 0 creates it, the rest
+// join via node 0), and starts their stabilizers. Callers must call Stop
+// when done.
+func NewHarness(n int, space domain.Space, basePort int, lgr logger.Logger) (*Harness, error) {
+	h := &Harness{
+		Controller: testfaults.NewController(),
+		space:      space,
+		lgr:        lgr,
+	}
+
+	for i := 0; i < n; i++ {
+		addr := fmt.Sprintf("127.0.0.1:%d", basePort+i)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			h.Stop()
+			return nil, fmt.Errorf("chaostest: listen on %s: %w", addr, err)
+		}
+
+		id := space.NewIdFromString(addr)
+		self := &domain.Node{ID: id, Addr: addr}
+		nodeLgr := lgr.Named(fmt.Sprintf("node-%d", i))
+
+		cp := client2.New(id, addr, defaultFailureTimeout, client2.WithLogger(nodeLgr.Named("clientpool")))
+		dialer := NewFaultyDialer(cp, h.Controller, addr)
+		store := storage.NewMemoryStorage(nodeLgr.Named("storage"))
+		rt := chord.NewRoutingTable(self, space, nodeLgr.Named("routingtable"))
+
+		node := chord.New(space, cp, store,
+			chord.WithRoutingTable(rt),
+			chord.WithLogger(nodeLgr),
+			chord.WithDialer(dialer),
+		)
+
+		srv, err := server2.New(lis, node, nil, server2.WithLogger(nodeLgr.Named("grpc-server")))
+		if err != nil {
+			_ = lis.Close()
+			h.Stop()
+			return nil, fmt.Errorf("chaostest: start gRPC server on %s: %w", addr, err)
+		}
+		go func() { _ = srv.Start() }()
+
+		h.nodes = append(h.nodes, node)
+		h.servers = append(h.servers, srv)
+		h.cps = append(h.cps, cp)
+	}
+
+	h.nodes[0].CreateNewDHT()
+	for i := 1; i < len(h.nodes); i++ {
+		if err := h.nodes[i].Join([]string{h.nodes[0].Self().Addr}); err != nil {
+			h.Stop()
+			return nil, fmt.Errorf("chaostest: node %d failed to join: %w", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	for _, node := range h.nodes {
+		node.StartStabilizers(ctx, 200*time.Millisecond, 200*time.Millisecond, 200*time.Millisecond)
+	}
+
+	return h, nil
+}
+
+// Nodes returns every node in the ring, in the order they were started.
+func (h *Harness) Nodes() []*chord.Node {
+	return h.nodes
+}
+
+// Stop cancels every node's stabilizers and stops their gRPC servers.
+func (h *Harness) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	for _, srv := range h.servers {
+		srv.Stop()
+	}
+	for _, node := range h.nodes {
+		node.Stop()
+	}
+}
+
+// KillPredecessor cuts all traffic between victim and its current
+// predecessor, in both directions, so victim's checkPredecessorLoop
+// observes it as unreachable on its next tick and clears it — the same
+// outcome as the predecessor actually crashing.
+func (h *Harness) KillPredecessor(victim *chord.Node) {
+	pred := victim.Predecessor()
+	if pred == nil {
+		return
+	}
+	h.Controller.Partition([]string{victim.Self().Addr, pred.Addr})
+}
+
+// PartitionSuccessorList cuts all traffic between node and every member of
+// its current successor list, without partitioning those successors from
+// each other, so node's stabilizeLoop can no longer reach any of them.
+func (h *Harness) PartitionSuccessorList(node *chord.Node) {
+	var others []string
+	for _, succ := range node.SuccessorList() {
+		if succ != nil {
+			others = append(others, succ.Addr)
+		}
+	}
+	h.Controller.Isolate(node.Self().Addr, others)
+}
+
+// SlowNotify delays every call node makes to its current first successor
+// by delay, rather than dropping it. Since stabilize dials its successor
+// once for GetPredecessor and again for Notify and GetSuccessorList, each
+// of those dials is delayed; this approximates slowing Notify
+// specifically without needing per-RPC-kind fault granularity.
+func (h *Harness) SlowNotify(node *chord.Node, delay time.Duration) {
+	succ := node.SuccessorList()
+	if len(succ) == 0 || succ[0] == nil {
+		return
+	}
+	h.Controller.Set(node.Self().Addr, succ[0].Addr, testfaults.FaultPlan{
+		LatencyDist: func() time.Duration { return delay },
+	})
+}
+
+// AssertConverged waits for roughly rounds stabilization intervals, then
+// checks that the ring is well-formed: every node's successor's
+// predecessor is that node itself, via a live RPC to the successor rather
+// than trusting the node's own cached view of it. It returns the first
+// inconsistency found, or nil if the ring has converged.
+func (h *Harness) AssertConverged(rounds int, interval time.Duration) error {
+	time.Sleep(time.Duration(rounds) * interval)
+
+	for _, node := range h.nodes {
+		succ := node.SuccessorList()
+		if len(succ) == 0 || succ[0] == nil {
+			return fmt.Errorf("chaostest: node %s has no successor", node.Self().Addr)
+		}
+
+		succCli, err := h.cps[0].GetFromPool(succ[0].Addr)
+		if err != nil {
+			return fmt.Errorf("chaostest: dial %s to verify convergence: %w", succ[0].Addr, err)
+		}
+
+		succPred, err := client2.GetPredecessor(context.Background(), succCli, &h.space)
+		if err != nil {
+			return fmt.Errorf("chaostest: ask %s for its predecessor: %w", succ[0].Addr, err)
+		}
+		if succPred == nil || !succPred.ID.Equal(node.Self().ID) {
+			return fmt.Errorf("chaostest: node %s's successor %s does not point its predecessor back",
+				node.Self().Addr, succ[0].Addr)
+		}
+	}
+	return nil
+}
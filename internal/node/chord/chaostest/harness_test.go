@@ -0,0 +1,74 @@
+package chaostest
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"testing"
+	"time"
+)
+
+func testSpace() domain.Space {
+	return domain.Space{Bits: 16, ByteLen: 2, GraphGrade: 2, SuccListSize: 3}
+}
+
+func TestHarnessConvergesAfterStart(t *testing.T) {
+	h, err := NewHarness(4, testSpace(), 17100, logger.New(nil))
+	if err != nil {
+		t.Fatalf("NewHarness() err: %v", err)
+	}
+	t.Cleanup(h.Stop)
+
+	if err := h.AssertConverged(20, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not converge after starting: %v", err)
+	}
+}
+
+func TestHarnessConvergesAfterKillPredecessor(t *testing.T) {
+	h, err := NewHarness(4, testSpace(), 17200, logger.New(nil))
+	if err != nil {
+		t.Fatalf("NewHarness() err: %v", err)
+	}
+	t.Cleanup(h.Stop)
+
+	if err := h.AssertConverged(20, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not converge before fault injection: %v", err)
+	}
+
+	h.KillPredecessor(h.Nodes()[0])
+
+	if err := h.AssertConverged(30, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not re-converge after KillPredecessor: %v", err)
+	}
+}
+
+func TestHarnessConvergesAfterPartitionSuccessorList(t *testing.T) {
+	h, err := NewHarness(5, testSpace(), 17300, logger.New(nil))
+	if err != nil {
+		t.Fatalf("NewHarness() err: %v", err)
+	}
+	t.Cleanup(h.Stop)
+
+	if err := h.AssertConverged(20, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not converge before fault injection: %v", err)
+	}
+
+	h.PartitionSuccessorList(h.Nodes()[1])
+
+	if err := h.AssertConverged(30, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not re-converge after PartitionSuccessorList: %v", err)
+	}
+}
+
+func TestHarnessConvergesWithSlowNotify(t *testing.T) {
+	h, err := NewHarness(4, testSpace(), 17400, logger.New(nil))
+	if err != nil {
+		t.Fatalf("NewHarness() err: %v", err)
+	}
+	t.Cleanup(h.Stop)
+
+	h.SlowNotify(h.Nodes()[0], 500*time.Millisecond)
+
+	if err := h.AssertConverged(30, 100*time.Millisecond); err != nil {
+		t.Fatalf("ring did not converge despite a slow (not dropped) Notify call: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package chord
+
+import client2 "KoordeDHT/internal/node/client"
+
+// PoolDialer abstracts dialing a pooled client connection to addr. It
+// exists so a test can substitute a faulty implementation (see
+// chaostest.FaultyDialer) in front of stabilize, LookUp (and so
+// fixFinger, which resolves fingers through it), and checkPredecessor,
+// without touching their RPC call sites. *client2.Pool satisfies this
+// interface, and is what New uses by default.
+type PoolDialer interface {
+	GetFromPool(addr string) (*client2.Client, error)
+}
+
+// WithDialer overrides the PoolDialer that stabilize, LookUp, and
+// checkPredecessor use to reach a peer, in place of the client pool
+// passed to New. Every other use of the client pool (Join, Leave,
+// Put/Get/Delete forwarding, streaming, hot-key replication) is
+// unaffected, since those aren't the RPC paths chaos testing targets.
+func WithDialer(d PoolDialer) Option {
+	return func(n *Node) {
+		n.dial = d
+	}
+}
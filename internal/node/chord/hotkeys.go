@@ -0,0 +1,88 @@
+package chord
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	client2 "KoordeDHT/internal/node/client"
+	"context"
+	"time"
+)
+
+// defaultHotKeyThreshold and defaultHotKeyReplicaFanout are the defaults
+// for the fields Option overrides: WithHotKeyThreshold and
+// WithHotKeyReplicaFanout.
+const (
+	defaultHotKeyThreshold     = 50
+	defaultHotKeyReplicaFanout = 2
+)
+
+// hotKeyReplicationInterval is fixed, matching fixFingersLoop and
+// checkPredecessorLoop, which also run on a fixed schedule rather than one
+// configured through StartStabilizers.
+const hotKeyReplicationInterval = 2 * time.Second
+
+// hotKeyReplicationLoop periodically pushes replicas of frequently
+// requested keys to this node's immediate successors. See
+// StartStabilizers for how it's started and stopped.
+func (n *Node) hotKeyReplicationLoop(ctx context.Context) {
+	ticker := time.NewTicker(hotKeyReplicationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.replicateHotKeys()
+		}
+	}
+}
+
+// replicateHotKeys pushes a copy of every locally stored key whose
+// estimated request count (from hotKeys, see LookUp) exceeds
+// hotKeyThreshold to this node's hotKeyReplicaFanout immediate successors,
+// so a subsequent Get served by any of those successors returns
+// immediately instead of forwarding back to us (see Node.Get).
+// SuccessorList is re-read on every tick, so a membership change
+// re-targets replicas to the current successors without a separate
+// re-placement step.
+func (n *Node) replicateHotKeys() {
+	successors := n.rt.SuccessorList()
+	if len(successors) == 0 {
+		return
+	}
+	fanout := n.hotKeyReplicaFanout
+	if fanout > len(successors) {
+		fanout = len(successors)
+	}
+	targets := successors[:fanout]
+
+	for _, hk := range n.hotKeys.TopK() {
+		if hk.EstCount <= n.hotKeyThreshold {
+			continue
+		}
+
+		// RetrieveLocal also filters out keys that aren't ours to
+		// replicate: we only ever see them here if we stored them,
+		// either as the owner or as a previously placed replica.
+		res, err := n.RetrieveLocal(hk.ID)
+		if err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+		for _, succ := range targets {
+			cli, err := n.cp.GetFromPool(succ.Addr)
+			if err != nil {
+				n.lgr.Warn("replicateHotKeys: failed to dial successor",
+					logger.F("succ_addr", succ.Addr), logger.F("err", err))
+				continue
+			}
+			if _, err := client2.StoreRemote(ctx, cli, []domain.Resource{res}); err != nil {
+				n.lgr.Warn("replicateHotKeys: failed to push replica",
+					logger.F("key", hk.ID.ToHexString(true)),
+					logger.F("succ_addr", succ.Addr), logger.F("err", err))
+			}
+		}
+		cancel()
+	}
+}
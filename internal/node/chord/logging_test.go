@@ -0,0 +1,46 @@
+package chord
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/dht"
+	"testing"
+)
+
+func TestNotifyLogsPredecessorChange(t *testing.T) {
+	space := domain.Space{
+		Bits:         8,
+		ByteLen:      1,
+		GraphGrade:   2,
+		SuccListSize: 3,
+	}
+
+	selfNode := &domain.Node{
+		ID:   domain.ID{0x80},
+		Addr: "127.0.0.1:4000",
+	}
+
+	hook := logger.NewMemoryHook(logger.InfoLevel)
+	lgr := logger.New(nil)
+	lgr.AddHook(hook)
+
+	rt := NewRoutingTable(selfNode, space, lgr)
+	n := &Node{lgr: lgr, rt: rt, events: dht.NewRoutingEventBus()}
+
+	firstPred := &domain.Node{ID: domain.ID{0x10}, Addr: "127.0.0.1:4010"}
+	n.Notify(firstPred)
+
+	secondPred := &domain.Node{ID: domain.ID{0x20}, Addr: "127.0.0.1:4020"}
+	n.Notify(secondPred)
+
+	entries := hook.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("MemoryHook recorded %d entries, expected 2", len(entries))
+	}
+	if entries[0].Message != "Notify: set predecessor (was nil)" {
+		t.Errorf("entries[0].Message = %q, expected the initial-predecessor event", entries[0].Message)
+	}
+	if entries[1].Message != "Notify: updated predecessor" {
+		t.Errorf("entries[1].Message = %q, expected the predecessor-change event", entries[1].Message)
+	}
+}
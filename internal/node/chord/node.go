@@ -4,33 +4,102 @@ import (
 	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/bootstrap"
 	client2 "KoordeDHT/internal/node/client"
 	"KoordeDHT/internal/node/ctxutil"
 	"KoordeDHT/internal/node/dht"
 	"KoordeDHT/internal/node/storage"
 	"context"
 	"fmt"
+	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// joinDialFraction and lookupForwardFraction bound how much of the caller's
+// remaining deadline a single hop (a bootstrap attempt, or a forwarded
+// lookup) is allowed to consume, so a recursive operation across several
+// hops always leaves budget for the later ones.
+const (
+	joinDialFraction      = 0.5
+	lookupForwardFraction = 0.5
+)
+
 type Node struct {
-	lgr logger.Logger
-	s   *storage.Storage
-	cp  *client2.Pool
-	rt  *RoutingTable
+	lgr    logger.Logger
+	s      *storage.Storage
+	cp     *client2.Pool
+	rt     *RoutingTable
+	dialer *bootstrap.Dialer
+
+	// dial is the PoolDialer stabilize, LookUp, and checkPredecessor use
+	// to reach a peer. It defaults to cp, but WithDialer can substitute a
+	// chaostest.FaultyDialer in tests.
+	dial PoolDialer
 
 	// Chord specific state
-	mu sync.RWMutex
+	mu              sync.RWMutex
+	stabilizeCancel context.CancelFunc
+
+	// events publishes predecessor/successor-list mutations made by
+	// Notify, stabilize, and checkPredecessor, for GET /debug/watch
+	// subscribers (see dht.RoutingEventSource).
+	events *dht.RoutingEventBus
+
+	// lookupHops records, for every top-level LookUp call, whether this
+	// node resolved it locally (0) or had to forward once to its closest
+	// preceding node (1). It does not see hops taken further down a
+	// forwarded chain on other nodes, so it undercounts true end-to-end
+	// path length; the protocol has no hop-count field to report that
+	// back. Exposed via CollectExtra, satisfying metrics.MetricsSource.
+	lookupHops prometheus.Histogram
+
+	// checksumMismatches counts resources that failed domain.VerifyResource
+	// in StoreLocal, RetrieveLocal, or VerifyIntegrity, surfaced via
+	// RoutingMetrics.ChecksumMismatchCount.
+	checksumMismatches atomic.Uint64
+
+	// stabilizeRounds and stabilizeDurationNs accumulate wall-clock timing
+	// across stabilize() calls, surfaced via
+	// RoutingMetrics.AvgStabilizationRoundMs.
+	stabilizeRounds     atomic.Uint64
+	stabilizeDurationNs atomic.Int64
+
+	// hotKeys is a bounded top-K frequency estimator (see
+	// dht.HotKeyTracker) over every key resolved by LookUp, surfaced via
+	// RoutingMetrics.HotKeys. hotKeyReplicationLoop (hotkeys.go) consults
+	// it to decide which locally owned keys to proactively replicate.
+	hotKeys *dht.HotKeyTracker
+
+	// hotKeyThreshold is the estimated request count above which a locally
+	// stored key is proactively replicated to hotKeyReplicaFanout
+	// immediate successors.
+	hotKeyThreshold     uint64
+	hotKeyReplicaFanout int
 }
 
 func New(space domain.Space, clientpool *client2.Pool, storage *storage.Storage, opts ...Option) *Node {
 	n := &Node{
-		lgr: &logger.NopLogger{},
-		cp:  clientpool,
-		s:   storage,
+		lgr:    &logger.NopLogger{},
+		cp:     clientpool,
+		dial:   clientpool,
+		s:      storage,
+		dialer: bootstrap.NewDialer(bootstrap.DefaultInitialResolveDelay, bootstrap.DefaultMaxResolveDelay),
+		events: dht.NewRoutingEventBus(),
+		lookupHops: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "koorde",
+			Name:      "lookup_hops",
+			Help:      "Hops this node observed directly while resolving a LookUp (0 = resolved locally, 1 = forwarded once); see Node.lookupHops for why this undercounts the true end-to-end path.",
+			Buckets:   []float64{0, 1, 2, 3, 4, 5},
+		}),
+		hotKeys:             dht.NewHotKeyTracker(dht.DefaultHotKeyTrackerSize),
+		hotKeyThreshold:     defaultHotKeyThreshold,
+		hotKeyReplicaFanout: defaultHotKeyReplicaFanout,
 	}
 	// Apply options
 	for _, opt := range opts {
@@ -57,15 +126,38 @@ func (n *Node) Join(peers []string) error {
 	var succ *domain.Node
 	var lastErr error
 
-	// Try each peer
+	// Give the whole Join call a single budget, then split it across
+	// bootstrap attempts so a few dead peers at the front of the list can't
+	// exhaust the time available for the peers that would have worked.
+	joinCtx, joinCancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer joinCancel()
+
+	// Candidate peers, excluding self. The dialer decides which of these is
+	// actually eligible to dial right now based on prior dial history.
+	candidates := make([]string, 0, len(peers))
 	for _, addr := range peers {
-		if addr == self.Addr {
-			continue
+		if addr != self.Addr {
+			candidates = append(candidates, addr)
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	}
+
+	// Consult the Dialer to pick the next eligible peer instead of re-dialing
+	// dead addresses tight-loop style: peers that failed recently are skipped
+	// until their backoff window elapses.
+	for len(candidates) > 0 {
+		addr, ok := n.dialer.Next(candidates)
+		if !ok {
+			lastErr = fmt.Errorf("join: all candidate peers are within backoff")
+			break
+		}
+		candidates = removeAddr(candidates, addr)
+
+		ctx, cancel := ctxutil.WithDeadlineFraction(joinCtx, joinDialFraction)
+		n.dialer.RecordAttempt(addr)
 		cli, conn, err := n.cp.DialEphemeral(addr)
 		if err != nil {
 			lastErr = fmt.Errorf("join: failed to dial bootstrap %s: %w", addr, err)
+			n.dialer.RecordFailure(addr)
 			cancel()
 			continue
 		}
@@ -76,6 +168,7 @@ func (n *Node) Join(peers []string) error {
 		conn.Close()
 
 		if lastErr == nil && succ != nil {
+			n.dialer.RecordSuccess(addr)
 			if succ.ID.Equal(self.ID) {
 				return fmt.Errorf("join: there is already a node with the same ID")
 			}
@@ -84,6 +177,7 @@ func (n *Node) Join(peers []string) error {
 				logger.FNode("successor", succ))
 			break
 		}
+		n.dialer.RecordFailure(addr)
 	}
 
 	if succ == nil {
@@ -99,8 +193,105 @@ func (n *Node) Join(peers []string) error {
 	return nil
 }
 
+// Leave gracefully removes this node from the ring: it hands off its
+// locally stored resources to its successor, tells the successor and
+// predecessor to repair their routing state, and only then stops the
+// background stabilizers and closes the client pool.
 func (n *Node) Leave() error {
-	return nil // TODO implementation
+	self := n.rt.Self()
+	succ := n.rt.FirstSuccessor()
+	pred := n.rt.GetPredecessor()
+
+	if succ == nil || succ.ID.Equal(self.ID) {
+		// Last node in the ring - nothing to hand off or notify.
+		n.stopStabilizers()
+		n.Stop()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+
+	// 1. Snapshot everything we're storing locally.
+	resources := n.GetAllResourceStored()
+
+	// 2. Stream the snapshot to the successor so it owns every key we did.
+	succCli, err := n.cp.GetFromPool(succ.Addr)
+	if err != nil {
+		return fmt.Errorf("leave: failed to dial successor %s: %w", succ.Addr, err)
+	}
+	if len(resources) > 0 {
+		if err := client2.BulkStoreRemote(ctx, succCli, resources); err != nil {
+			return fmt.Errorf("leave: failed to hand off resources to successor %s: %w", succ.Addr, err)
+		}
+	}
+
+	// 3. Tell the successor about our predecessor so it can adopt it. The
+	// resource handoff itself already happened in step 2 via
+	// BulkStoreRemote, so this notification doesn't carry Resources too.
+	if err := client2.LeaveRemote(ctx, succCli, dht.LeaveNotification{
+		Leaving:     self,
+		Predecessor: pred,
+	}); err != nil {
+		return fmt.Errorf("leave: failed to notify successor %s: %w", succ.Addr, err)
+	}
+
+	// 4. Tell the predecessor about our successor so its successor list stays correct.
+	if pred != nil && !pred.ID.Equal(self.ID) {
+		predCli, err := n.cp.GetFromPool(pred.Addr)
+		if err != nil {
+			n.lgr.Warn("leave: failed to dial predecessor", logger.F("pred_addr", pred.Addr), logger.F("err", err))
+		} else if err := client2.LeaveRemote(ctx, predCli, dht.LeaveNotification{
+			Leaving:   self,
+			Successor: succ,
+		}); err != nil {
+			n.lgr.Warn("leave: failed to notify predecessor", logger.F("pred_addr", pred.Addr), logger.F("err", err))
+		}
+	}
+
+	// 5. Only now stop the stabilizers and close the client pool.
+	n.stopStabilizers()
+	n.Stop()
+
+	n.lgr.Info("leave: completed gracefully",
+		logger.FNode("self", self),
+		logger.FNode("successor", succ),
+		logger.F("handed_off_resources", len(resources)))
+	return nil
+}
+
+// stopStabilizers cancels the context passed to StartStabilizers, if any,
+// so the background goroutines exit before the client pool is closed.
+func (n *Node) stopStabilizers() {
+	n.mu.RLock()
+	cancel := n.stabilizeCancel
+	n.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// HandleLeave applies the routing update carried by a peer's graceful leave.
+// If notice carries a Predecessor, we are the leaving node's successor: we
+// adopt its predecessor (the resources themselves already arrived via
+// BulkStoreRemote before this notification). If it carries a Successor, we
+// are its predecessor: we adopt the successor as our own.
+func (n *Node) HandleLeave(ctx context.Context, notice dht.LeaveNotification) error {
+	if notice.Predecessor != nil {
+		n.rt.SetPredecessor(notice.Predecessor)
+		n.lgr.Info("HandleLeave: adopted predecessor",
+			logger.FNode("leaving", notice.Leaving),
+			logger.FNode("new_pred", notice.Predecessor))
+	}
+
+	if notice.Successor != nil {
+		n.rt.SetSuccessor(0, notice.Successor)
+		n.lgr.Info("HandleLeave: adopted successor",
+			logger.FNode("leaving", notice.Leaving),
+			logger.FNode("new_succ", notice.Successor))
+	}
+
+	return nil
 }
 
 func (n *Node) Stop() {
@@ -109,10 +300,37 @@ func (n *Node) Stop() {
 	}
 }
 
+// RemoveStatic purges addr's dial history so the next Join retries it
+// immediately instead of waiting out its current backoff.
+// Operators call this after fixing a peer they know was previously dead.
+func (n *Node) RemoveStatic(addr string) {
+	n.dialer.RemoveStatic(addr)
+}
+
+// removeAddr returns addrs with the first occurrence of target removed.
+func removeAddr(addrs []string, target string) []string {
+	out := make([]string, 0, len(addrs))
+	removed := false
+	for _, a := range addrs {
+		if !removed && a == target {
+			removed = true
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
 func (n *Node) Put(ctx context.Context, res domain.Resource) error {
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return err
 	}
+
+	// Stamp the integrity metadata once, here, so it travels with res
+	// through every subsequent hop (forward to successor, stabilization
+	// replica transfer, successor-list handoff) without being recomputed.
+	res.Checksum, res.StrongDigest = domain.ChecksumResource(res.Value, true)
+
 	succ, err := n.LookUp(ctx, res.Key)
 	if err != nil {
 		return err
@@ -135,6 +353,14 @@ func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error)
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
 	}
+
+	// A hot-key replica (see hotKeyReplicationLoop) lets any replica
+	// holder serve a Get directly, so check local storage before paying
+	// for a LookUp and a routing hop to the actual owner.
+	if res, err := n.RetrieveLocal(id); err == nil {
+		return &res, nil
+	}
+
 	succ, err := n.LookUp(ctx, id)
 	if err != nil {
 		return nil, err
@@ -173,10 +399,58 @@ func (n *Node) Delete(ctx context.Context, id domain.ID) error {
 	return client2.RemoveRemote(ctx, cli, id)
 }
 
+// PutStream stores the resource at id from r without buffering the whole
+// payload in memory. Like Put, it forwards to the responsible successor if
+// that isn't self; the remote hop tunnels the stream over a ReadAt-style
+// RPC rather than marshaling the whole object.
+func (n *Node) PutStream(ctx context.Context, id domain.ID, meta dht.ResourceMeta, r io.Reader) error {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return err
+	}
+
+	succ, err := n.LookUp(ctx, id)
+	if err != nil {
+		return err
+	}
+	if succ.ID.Equal(n.rt.Self().ID) {
+		return n.s.PutStream(id, meta, r)
+	}
+
+	cli, err := n.cp.GetFromPool(succ.Addr)
+	if err != nil {
+		return err
+	}
+	return client2.PutStreamRemote(ctx, cli, id, meta, r)
+}
+
+// GetStream retrieves the resource at id as a seekable stream, forwarding
+// to the responsible successor if that isn't self.
+func (n *Node) GetStream(ctx context.Context, id domain.ID) (dht.ResourceReadSeekCloser, error) {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	succ, err := n.LookUp(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if succ.ID.Equal(n.rt.Self().ID) {
+		return n.s.GetStream(id)
+	}
+
+	cli, err := n.cp.GetFromPool(succ.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return client2.GetStreamRemote(ctx, cli, n.Space(), id)
+}
+
 func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
 	// Chord lookup logic
 	// Find successor of id
 
+	n.hotKeys.Observe(id)
+
 	// 1. Check if id is in (self, successor]
 	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
@@ -185,6 +459,7 @@ func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
 	}
 
 	if id.Between(self.ID, succ.ID) || id.Equal(succ.ID) {
+		n.lookupHops.Observe(0)
 		return succ, nil
 	}
 
@@ -198,14 +473,26 @@ func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
 	}
 
 	// 4. Forward query to closest
-	cli, err := n.cp.GetFromPool(closest.Addr)
+	cli, err := n.dial.GetFromPool(closest.Addr)
 	if err != nil {
 		return nil, err
 	}
 
+	// Only spend a fraction of what's left of the caller's deadline on this
+	// hop. A recursive lookup can take O(log N) hops, so passing the full
+	// deadline through unchanged would let a single slow hop starve every
+	// hop after it.
+	fctx, cancel := ctxutil.WithDeadlineFraction(ctx, lookupForwardFraction)
+	defer cancel()
+
+	n.lgr.Debug("lookup: forwarding",
+		logger.F("target", id.ToHexString(true)),
+		logger.FNode("closest", closest))
+
 	// Use FindSuccessor RPC
 	// We can use Initial mode for simplicity, as Chord doesn't need Step state
-	return client2.FindSuccessorStart(ctx, cli, n.Space(), id)
+	n.lookupHops.Observe(1)
+	return client2.FindSuccessorStart(fctx, cli, n.Space(), id)
 }
 
 func (n *Node) HandleFindSuccessor(ctx context.Context, req *dhtv1.FindSuccessorRequest) (*dhtv1.FindSuccessorResponse, error) {
@@ -234,11 +521,6 @@ func (n *Node) Predecessor() *domain.Node {
 	return n.rt.GetPredecessor()
 }
 
-func (n *Node) HandleLeave(leaveNode *domain.Node) error {
-	// TODO
-	return nil
-}
-
 func (n *Node) Notify(node *domain.Node) {
 	if node == nil {
 		return
@@ -250,10 +532,12 @@ func (n *Node) Notify(node *domain.Node) {
 	// If we have no predecessor, or if node is between pred and self, update predecessor
 	if pred == nil {
 		n.rt.SetPredecessor(node)
+		n.events.Publish(dht.EventPredecessorChanged, 0, nil, node)
 		n.lgr.Info("Notify: set predecessor (was nil)",
 			logger.FNode("new_pred", node))
 	} else if node.ID.Between(pred.ID, self.ID) {
 		n.rt.SetPredecessor(node)
+		n.events.Publish(dht.EventPredecessorChanged, 0, pred, node)
 		n.lgr.Info("Notify: updated predecessor",
 			logger.FNode("old_pred", pred),
 			logger.FNode("new_pred", node))
@@ -276,25 +560,87 @@ func (n *Node) GetAllResourceStored() []domain.Resource {
 	return n.s.All()
 }
 
+// StoreLocal stores res, verifying its integrity metadata first. It's the
+// landing point for replica handoff during stabilization and successor-list
+// handoff (see Leave/HandleLeave), as well as a successful local Put, so
+// this is where inbound corruption gets caught before it's persisted.
 func (n *Node) StoreLocal(ctx context.Context, res domain.Resource) error {
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("StoreLocal: integrity check failed", logger.F("key", res.Key.ToHexString(true)), logger.F("err", err))
+		return err
+	}
 	n.s.Put(res)
 	return nil
 }
 
+// RetrieveLocal fetches the resource at id and verifies its integrity
+// metadata before returning it, so a Get surfaces silent corruption instead
+// of serving a corrupted value.
 func (n *Node) RetrieveLocal(id domain.ID) (domain.Resource, error) {
-	return n.s.Get(id)
+	res, err := n.s.Get(id)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("RetrieveLocal: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return domain.Resource{}, err
+	}
+	return res, nil
 }
 
 func (n *Node) RemoveLocal(id domain.ID) error {
 	return n.s.Delete(id)
 }
 
+// VerifyIntegrity re-checks the locally stored resource at id against its
+// integrity metadata without going through the Get path, for use by
+// background scrub passes that sweep every locally stored key.
+func (n *Node) VerifyIntegrity(id domain.ID) error {
+	res, err := n.s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("VerifyIntegrity: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return err
+	}
+	return nil
+}
+
 func (n *Node) DeBruijnList() []*domain.Node {
 	return nil // Chord doesn't use De Bruijn
 }
 
+// Events returns the RoutingEventBus that Notify, stabilize, and
+// checkPredecessor publish to, satisfying dht.RoutingEventSource.
+func (n *Node) Events() *dht.RoutingEventBus {
+	return n.events
+}
+
+// CollectExtra emits lookupHops, satisfying metrics.MetricsSource. The
+// series carries no node_id/protocol labels of its own since
+// prometheus.Histogram doesn't support per-Observe labels; the exporter's
+// other series supply those from RoutingMetrics instead, and in this
+// deployment model one process is always exactly one node, so the omission
+// doesn't collapse distinct series together.
+func (n *Node) CollectExtra(ch chan<- prometheus.Metric, nodeID string) {
+	ch <- n.lookupHops
+}
+
 func (n *Node) RoutingMetrics() dht.RoutingMetrics {
-	return dht.RoutingMetrics{Protocol: "chord"}
+	var avgStabilizeMs float64
+	if rounds := n.stabilizeRounds.Load(); rounds > 0 {
+		avgStabilizeMs = float64(n.stabilizeDurationNs.Load()) / float64(rounds) / float64(time.Millisecond)
+	}
+	return dht.RoutingMetrics{
+		Protocol:                "chord",
+		ChecksumMismatchCount:   n.checksumMismatches.Load(),
+		AvgStabilizationRoundMs: avgStabilizeMs,
+		HotKeys:                 n.hotKeys.TopK(),
+	}
 }
 
 // FingerList returns all non-nil finger table entries (Chord-specific)
@@ -15,3 +15,19 @@ func WithRoutingTable(rt *RoutingTable) Option {
 		n.rt = rt
 	}
 }
+
+// WithHotKeyThreshold overrides the estimated request count above which
+// hotKeyReplicationLoop proactively replicates a locally stored key.
+func WithHotKeyThreshold(threshold uint64) Option {
+	return func(n *Node) {
+		n.hotKeyThreshold = threshold
+	}
+}
+
+// WithHotKeyReplicaFanout overrides how many immediate successors receive
+// a copy of a hot key.
+func WithHotKeyReplicaFanout(fanout int) Option {
+	return func(n *Node) {
+		n.hotKeyReplicaFanout = fanout
+	}
+}
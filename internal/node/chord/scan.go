@@ -0,0 +1,153 @@
+package chord
+
+import (
+	"KoordeDHT/internal/domain"
+	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/dht"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// defaultScanBatchSize is used when ScanOptions.BatchSize is unset, chosen
+// to keep a single hop's RPC response small without requiring most scans to
+// make more than a handful of round trips per node.
+const defaultScanBatchSize = 256
+
+// Scan implements dht.DHTNode.Scan by walking successors starting at start,
+// pulling each node's locally stored resources in (low, end] before hopping
+// on to its successor, so callers get every matching resource in the range
+// without needing to know the ring's membership up front.
+func (n *Node) Scan(ctx context.Context, start, end domain.ID, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultScanBatchSize
+	}
+
+	low := start
+	addr := n.rt.Self().Addr
+	if opts.Cursor != "" {
+		cursor, err := dht.DecodeScanCursor(*n.Space(), opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		low = cursor.LastID
+		addr = cursor.ServedBy
+	}
+
+	return &ringScanIterator{
+		n:         n,
+		sp:        *n.Space(),
+		end:       end,
+		low:       low,
+		curAddr:   addr,
+		batchSize: batchSize,
+	}, nil
+}
+
+// PrefixScan derives a (start, end] range from prefix/prefixBits via
+// dht.PrefixRange and delegates to Scan.
+func (n *Node) PrefixScan(ctx context.Context, prefix []byte, prefixBits int, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	start, end, err := dht.PrefixRange(*n.Space(), prefix, prefixBits)
+	if err != nil {
+		return nil, err
+	}
+	return n.Scan(ctx, start, end, opts)
+}
+
+// ringScanIterator implements dht.ResourceIterator by buffering one node's
+// worth of matching resources at a time and hopping to that node's
+// successor once it's exhausted for the remaining range.
+type ringScanIterator struct {
+	n         *Node
+	sp        domain.Space
+	end       domain.ID
+	low       domain.ID // exclusive lower bound of what's left to scan
+	curAddr   string    // node the current buffer was fetched from
+	batchSize int
+
+	buf []domain.Resource
+	pos int
+	cur domain.Resource
+
+	done bool
+	err  error
+}
+
+func (it *ringScanIterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+
+	for it.pos >= len(it.buf) {
+		if err := ctxutil.CheckContext(ctx); err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		if it.low.Equal(it.end) {
+			it.done = true
+			return false
+		}
+
+		resources, next, err := it.fetchBatch(ctx)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+
+		it.buf = resources
+		it.pos = 0
+		if len(it.buf) == 0 {
+			if next == nil || next.Addr == it.curAddr {
+				it.done = true
+				return false
+			}
+			it.curAddr = next.Addr
+		}
+	}
+
+	it.cur = it.buf[it.pos]
+	it.pos++
+	it.low = it.cur.Key
+	return true
+}
+
+// fetchBatch pulls up to batchSize resources in (low, end] from curAddr,
+// along with that node's first successor so Next can hop once curAddr is
+// exhausted for the remaining range.
+func (it *ringScanIterator) fetchBatch(ctx context.Context) ([]domain.Resource, *domain.Node, error) {
+	if it.curAddr == it.n.rt.Self().Addr {
+		var matches []domain.Resource
+		for _, res := range it.n.GetAllResourceStored() {
+			if res.Key.Between(it.low, it.end) {
+				matches = append(matches, res)
+			}
+		}
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Key.Cmp(matches[j].Key) < 0 })
+		if len(matches) > it.batchSize {
+			matches = matches[:it.batchSize]
+		}
+		return matches, it.n.rt.FirstSuccessor(), nil
+	}
+
+	cli, err := it.n.cp.GetFromPool(it.curAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan: failed to dial %s: %w", it.curAddr, err)
+	}
+	return client2.ScanRemote(ctx, cli, &it.sp, it.low, it.end, it.batchSize)
+}
+
+func (it *ringScanIterator) Resource() domain.Resource { return it.cur }
+
+func (it *ringScanIterator) Cursor() string {
+	return dht.EncodeScanCursor(dht.ScanCursor{LastID: it.low, ServedBy: it.curAddr})
+}
+
+func (it *ringScanIterator) Err() error { return it.err }
+
+// Close is a no-op: ringScanIterator borrows connections from the node's
+// client pool rather than holding any of its own.
+func (it *ringScanIterator) Close() error { return nil }
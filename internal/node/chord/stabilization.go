@@ -4,16 +4,26 @@ import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/dht"
 	"context"
 	"math/big"
 	"time"
 )
 
-// StartStabilizers starts the background stabilization goroutines.
+// StartStabilizers starts the background stabilization goroutines. The
+// cancel function it derives (stored on the Node) lets Leave stop them ahead
+// of the caller's own shutdown signal, so handoff/notification RPCs don't
+// race with the client pool being closed.
 func (n *Node) StartStabilizers(ctx context.Context, stabilizationInterval, deBruijnInterval, storageInterval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	n.mu.Lock()
+	n.stabilizeCancel = cancel
+	n.mu.Unlock()
+
 	go n.stabilizeLoop(ctx, stabilizationInterval)
 	go n.fixFingersLoop(ctx)
 	go n.checkPredecessorLoop(ctx)
+	go n.hotKeyReplicationLoop(ctx)
 }
 
 func (n *Node) stabilizeLoop(ctx context.Context, interval time.Duration) {
@@ -58,15 +68,26 @@ func (n *Node) checkPredecessorLoop(ctx context.Context) {
 }
 
 func (n *Node) stabilize() {
+	start := time.Now()
+	defer func() {
+		n.stabilizeRounds.Add(1)
+		n.stabilizeDurationNs.Add(time.Since(start).Nanoseconds())
+	}()
+
 	// 1. Get successor
 	succ := n.rt.FirstSuccessor()
 	if succ == nil {
 		return
 	}
 
-	// 2. Ask successor for its predecessor
+	// 2. Ask successor for its predecessor. Unlike Join's bootstrap dial
+	// (see Node.Join), this is the live successor, not a candidate being
+	// selected among several — it's contacted unconditionally every tick
+	// rather than being gated by the Dialer's backoff, since skipping a
+	// transiently-unreachable successor for up to its backoff ceiling would
+	// leave routing state unrepaired for just as long.
 	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-	cli, err := n.cp.GetFromPool(succ.Addr)
+	cli, err := n.dial.GetFromPool(succ.Addr)
 	if err != nil {
 		cancel()
 		return
@@ -83,14 +104,19 @@ func (n *Node) stabilize() {
 	// 3. If x is between self and successor, x is our new successor
 	if x != nil {
 		if x.ID.Between(n.rt.Self().ID, succ.ID) {
+			n.lgr.Info("stabilize: updated successor",
+				logger.FNode("old_succ", succ),
+				logger.FNode("new_succ", x))
+			oldSucc := succ
 			n.rt.SetSuccessor(0, x)
+			n.events.Publish(dht.EventSuccessorChanged, 0, oldSucc, x)
 			succ = x
 		}
 	}
 
 	// 4. Notify successor about self
 	ctx, cancel = context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-	cli, err = n.cp.GetFromPool(succ.Addr)
+	cli, err = n.dial.GetFromPool(succ.Addr)
 	if err != nil {
 		cancel()
 		return
@@ -116,12 +142,30 @@ func (n *Node) stabilize() {
 		}
 		// Update local list (SetSuccessorList logic needed in RoutingTable)
 		// For now, just update individual entries
+		oldList := n.rt.SuccessorList()
 		for i, node := range newList {
+			var old *domain.Node
+			if i < len(oldList) {
+				old = oldList[i]
+			}
 			n.rt.SetSuccessor(i, node)
+			if !sameNode(old, node) {
+				n.events.Publish(dht.EventSuccessorChanged, i, old, node)
+			}
 		}
 	}
 }
 
+// sameNode reports whether a and b refer to the same node (or are both
+// nil), for deciding whether a successor-list slot actually changed before
+// publishing a RoutingEvent.
+func sameNode(a, b *domain.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Addr == b.Addr && a.ID.Equal(b.ID)
+}
+
 func (n *Node) fixFinger(i int) {
 	// Calculate ID: (self + 2^i) mod 2^Bits
 	// This is the standard Chord finger table entry calculation
@@ -187,15 +231,19 @@ func (n *Node) checkPredecessor() {
 	}
 	// Ping predecessor
 	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-	cli, err := n.cp.GetFromPool(pred.Addr)
+	cli, err := n.dial.GetFromPool(pred.Addr)
 	if err != nil {
 		n.rt.SetPredecessor(nil)
+		n.events.Publish(dht.EventPredecessorChanged, 0, pred, nil)
 		cancel()
 		return
 	}
 	err = client2.Ping(ctx, cli)
 	cancel()
 	if err != nil {
+		n.lgr.Info("checkPredecessor: predecessor unreachable, clearing",
+			logger.FNode("pred", pred), logger.F("err", err))
 		n.rt.SetPredecessor(nil)
+		n.events.Publish(dht.EventPredecessorChanged, 0, pred, nil)
 	}
 }
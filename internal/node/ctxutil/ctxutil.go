@@ -0,0 +1,103 @@
+// Package ctxutil provides small context helpers shared across the DHT
+// node implementations.
+package ctxutil
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// CheckContext returns the context's error if it has already been canceled
+// or its deadline has passed, and nil otherwise. Call sites use this to fail
+// fast before doing any work on a context that is already dead.
+func CheckContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// WithDeadlineFraction returns a child context scoped to a fraction f of the
+// parent's remaining deadline budget: if ctx has a deadline, the child's
+// deadline is set to now + f*remaining. If ctx has no deadline, it falls
+// back to context.WithCancel so the child is still tied to the parent's
+// cancellation.
+//
+// This is used when forwarding a request across several hops (e.g. a
+// recursive Chord LookUp, or a bootstrap dial loop): spending the full
+// remaining budget on a single hop would starve every hop after it, so each
+// hop only gets a fraction (typically around 0.5) of what's left.
+func WithDeadlineFraction(ctx context.Context, f float64) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return context.WithDeadline(ctx, deadline)
+	}
+
+	budget := time.Duration(float64(remaining) * f)
+	return context.WithDeadline(ctx, time.Now().Add(budget))
+}
+
+// hopBudgetKey is the context key under which WithHopBudget stores its
+// remaining hop count. Unexported so only this package's accessors can
+// read or write it.
+type hopBudgetKey struct{}
+
+// hopBudgetMetadataKey is the gRPC metadata key WithHopBudget mirrors its
+// value under, so the budget survives a StoreRemote/RetrieveRemote call
+// into another process instead of being lost at the process boundary — a
+// plain context.Value never crosses an RPC. gRPC lower-cases metadata keys,
+// so this is written lowercase already.
+const hopBudgetMetadataKey = "x-koorde-hop-budget"
+
+// WithHopBudget returns a child context carrying a bounded hop counter,
+// initialized to n, and marks it as outgoing gRPC metadata so the next RPC
+// issued with it (e.g. client2.StoreRemote/RetrieveRemote) carries the
+// budget to the remote node. Request forwarding (e.g. simple.Node's
+// Put/Get/Delete forwarding to the node it believes is responsible) uses
+// this to bound how many times a request can be re-forwarded, so
+// membership skew across nodes can't turn into an infinite forwarding loop
+// — including across the process boundary, since HopsRemaining on the
+// receiving node recovers the same budget from incoming metadata.
+func WithHopBudget(ctx context.Context, n int) context.Context {
+	ctx = context.WithValue(ctx, hopBudgetKey{}, n)
+	return metadata.AppendToOutgoingContext(ctx, hopBudgetMetadataKey, strconv.Itoa(n))
+}
+
+// HopsRemaining returns the hop count stored by WithHopBudget and whether
+// ctx carries one at all. It first checks the same-process context.Value
+// WithHopBudget set directly; if that's absent, it falls back to the
+// incoming gRPC metadata WithHopBudget mirrored on the sending side, so a
+// node that receives a forwarded Put/Get/Delete as an RPC (rather than a
+// same-process call) still sees the caller's remaining budget instead of
+// restarting at defaultForwardHopBudget on every hop. Callers that get
+// ok == false are seeing the request's first hop and should initialize a
+// budget via WithHopBudget before forwarding further.
+func HopsRemaining(ctx context.Context) (int, bool) {
+	if n, ok := ctx.Value(hopBudgetKey{}).(int); ok {
+		return n, true
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	vals := md.Get(hopBudgetMetadataKey)
+	if len(vals) == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(vals[0])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,132 @@
+package dht
+
+import (
+	"KoordeDHT/internal/domain"
+	"sync"
+	"time"
+)
+
+// RoutingEventKind classifies what changed in a RoutingEvent.
+type RoutingEventKind string
+
+const (
+	EventPredecessorChanged RoutingEventKind = "predecessor_changed"
+	EventSuccessorChanged   RoutingEventKind = "successor_changed"
+	EventDeBruijnChanged    RoutingEventKind = "debruijn_slot_changed"
+)
+
+// RoutingEvent describes one mutation to a node's routing table. Before and
+// After are nil when the corresponding slot was empty, so subscribers can
+// tell "added" (Before nil) from "removed" (After nil) from "replaced"
+// (both set) just by inspecting them.
+type RoutingEvent struct {
+	Seq       uint64           `json:"seq"`
+	Timestamp time.Time        `json:"timestamp"`
+	Kind      RoutingEventKind `json:"kind"`
+
+	// Slot identifies which entry changed within Kind's list (successor
+	// list index, de Bruijn slot index); always 0 for
+	// EventPredecessorChanged, which has only one slot.
+	Slot int `json:"slot"`
+
+	Before *domain.Node `json:"before,omitempty"`
+	After  *domain.Node `json:"after,omitempty"`
+}
+
+// routingEventHistoryLimit bounds how many past events RoutingEventBus
+// retains for Since, trading unbounded memory growth for a finite
+// catch-up window on reconnect.
+const routingEventHistoryLimit = 256
+
+// RoutingEventBus fans a node's routing table mutations out to subscribers
+// (e.g. GET /debug/watch), each over its own buffered channel so one slow
+// subscriber can't block another subscriber or the stabilization loop that
+// publishes.
+type RoutingEventBus struct {
+	mu          sync.Mutex
+	seq         uint64
+	subscribers map[chan RoutingEvent]struct{}
+	history     []RoutingEvent
+}
+
+// NewRoutingEventBus creates an empty RoutingEventBus.
+func NewRoutingEventBus() *RoutingEventBus {
+	return &RoutingEventBus{subscribers: make(map[chan RoutingEvent]struct{})}
+}
+
+// Subscribe registers a new subscriber and returns its event channel. The
+// channel is buffered; if a subscriber falls behind and its buffer fills,
+// Publish drops that event for it rather than blocking the caller
+// (typically the stabilization loop) — the subscriber's next from_seq
+// catch-up via Since will see the gap.
+func (b *RoutingEventBus) Subscribe() <-chan RoutingEvent {
+	ch := make(chan RoutingEvent, 64)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes a subscriber and closes its channel.
+func (b *RoutingEventBus) Unsubscribe(ch <-chan RoutingEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.subscribers {
+		if c == ch {
+			delete(b.subscribers, c)
+			close(c)
+			return
+		}
+	}
+}
+
+// Publish records and fans out a routing change, assigning it the next
+// sequence number.
+func (b *RoutingEventBus) Publish(kind RoutingEventKind, slot int, before, after *domain.Node) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := RoutingEvent{
+		Seq:       b.seq,
+		Timestamp: time.Now(),
+		Kind:      kind,
+		Slot:      slot,
+		Before:    before,
+		After:     after,
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > routingEventHistoryLimit {
+		b.history = b.history[len(b.history)-routingEventHistoryLimit:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Since returns every retained event with Seq > fromSeq, for a reconnecting
+// subscriber's ?from_seq=N catch-up.
+func (b *RoutingEventBus) Since(fromSeq uint64) []RoutingEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]RoutingEvent, 0, len(b.history))
+	for _, e := range b.history {
+		if e.Seq > fromSeq {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// RoutingEventSource is implemented by DHTNode types that publish routing
+// table mutations to a RoutingEventBus. Callers should type-assert and
+// treat a failed assertion as "not supported for this protocol".
+type RoutingEventSource interface {
+	Events() *RoutingEventBus
+}
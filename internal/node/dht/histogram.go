@@ -0,0 +1,40 @@
+package dht
+
+import (
+	"math"
+	"time"
+)
+
+// Histogram bucket layout shared by every protocol implementation's
+// latency histograms (see logicnode.durationHistogram), so a consumer of
+// RoutingMetrics' BucketCounts fields — Prometheus exposition in
+// internal/metrics.Exporter, in particular — can reconstruct real bucket
+// boundaries without guessing at an implementation-private scheme.
+//
+// Layout, HDR-histogram-style but simplified for a fixed, small bucket
+// count: nanosecond values from HistMinNs to HistMaxNs are split into
+// octaves (successive power-of-2 ranges), and each octave is split into
+// HistSubBucketsPerOctave sub-buckets of equal width on a log scale (each
+// upper bound is 2^(1/HistSubBucketsPerOctave) times the last). HistMinNs=100ns
+// and HistMaxNs=60s span ~30 octaves, for 120 buckets plus one underflow
+// and one overflow bucket (HistBucketCount total).
+const (
+	HistMinNs              = int64(100)             // 100ns
+	HistMaxNs              = int64(60 * time.Second) // 60s
+	HistSubBucketsPerOctave = 4
+	HistOctaves             = 30 // ceil(log2(HistMaxNs/HistMinNs)), ~29.2
+	HistBucketCount         = HistSubBucketsPerOctave*HistOctaves + 2
+)
+
+// BucketUpperBoundNs returns the approximate upper edge, in nanoseconds,
+// of bucket i in the layout documented above. Bucket 0 is underflow
+// (<HistMinNs); bucket HistBucketCount-1 is overflow (>=HistMaxNs).
+func BucketUpperBoundNs(i int) int64 {
+	if i <= 0 {
+		return HistMinNs
+	}
+	if i >= HistBucketCount-1 {
+		return HistMaxNs
+	}
+	return int64(float64(HistMinNs) * math.Pow(2, float64(i)/float64(HistSubBucketsPerOctave)))
+}
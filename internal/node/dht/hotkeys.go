@@ -0,0 +1,93 @@
+package dht
+
+import (
+	"KoordeDHT/internal/domain"
+	"sort"
+	"sync"
+)
+
+// DefaultHotKeyTrackerSize is the default number of distinct keys a
+// HotKeyTracker keeps counters for (K in Misra-Gries/Space-Saving terms),
+// chosen to bound per-request overhead to O(K) regardless of how many
+// distinct keys actually stream through it.
+const DefaultHotKeyTrackerSize = 1024
+
+// HotKey is one entry of RoutingMetrics.HotKeys: a key and its estimated
+// request count over the tracker's observation window.
+type HotKey struct {
+	ID       domain.ID `json:"id"`
+	EstCount uint64    `json:"est_count"`
+}
+
+// HotKeyTracker is a bounded top-K frequency estimator over a stream of
+// requested keys, implementing the Misra-Gries heavy-hitters algorithm: it
+// never holds counters for more than capacity distinct keys, decrementing
+// every counter (and evicting any that hit zero) whenever a new key would
+// exceed that bound. This guarantees any key whose true frequency exceeds
+// (total observations)/capacity survives in the result, at the cost of
+// under-estimating (never over-estimating) the rest.
+type HotKeyTracker struct {
+	mu       sync.Mutex
+	capacity int
+	ids      map[string]domain.ID
+	counts   map[string]uint64
+}
+
+// NewHotKeyTracker returns a tracker bounded to capacity distinct keys. A
+// non-positive capacity falls back to DefaultHotKeyTrackerSize.
+func NewHotKeyTracker(capacity int) *HotKeyTracker {
+	if capacity <= 0 {
+		capacity = DefaultHotKeyTrackerSize
+	}
+	return &HotKeyTracker{
+		capacity: capacity,
+		ids:      make(map[string]domain.ID, capacity),
+		counts:   make(map[string]uint64, capacity),
+	}
+}
+
+// Observe records one request for id.
+func (t *HotKeyTracker) Observe(id domain.ID) {
+	key := string(id)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.counts[key]; ok {
+		t.counts[key]++
+		return
+	}
+	if len(t.counts) < t.capacity {
+		t.ids[key] = append(domain.ID(nil), id...)
+		t.counts[key] = 1
+		return
+	}
+
+	// At capacity: this is the Misra-Gries decrement step. Every tracked
+	// key loses one count, and any that reach zero are evicted, which
+	// bounds memory to capacity while never evicting a true heavy hitter.
+	for k, c := range t.counts {
+		if c <= 1 {
+			delete(t.counts, k)
+			delete(t.ids, k)
+		} else {
+			t.counts[k] = c - 1
+		}
+	}
+}
+
+// TopK returns every currently tracked key and its estimated count, sorted
+// by descending count. Counts are a lower bound on the true request count:
+// the Misra-Gries decrement step only ever undercounts.
+func (t *HotKeyTracker) TopK() []HotKey {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]HotKey, 0, len(t.counts))
+	for k, c := range t.counts {
+		out = append(out, HotKey{ID: t.ids[k], EstCount: c})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].EstCount > out[j].EstCount })
+	return out
+}
@@ -4,9 +4,26 @@ import (
 	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/domain"
 	"context"
+	"io"
 	"time"
 )
 
+// LeaveNotification is sent by a node that is gracefully leaving the ring to
+// its immediate neighbors so they can repair their routing state without
+// waiting for stabilization to notice the departure.
+//
+// A leaving node sends one LeaveNotification to its successor with
+// Predecessor set to its own predecessor (so the successor can adopt it),
+// and one to its predecessor with Successor set to its own successor (so the
+// predecessor's successor list stays correct). The leaving node's locally
+// stored resources are handed off to the successor separately, via
+// BulkStoreRemote, before this notification is sent.
+type LeaveNotification struct {
+	Leaving     *domain.Node
+	Predecessor *domain.Node
+	Successor   *domain.Node
+}
+
 // DHTNode defines the common interface for a Distributed Hash Table node.
 // Both Koorde and Chord implementations must satisfy this interface
 // to be used by the server and cache layers.
@@ -49,8 +66,10 @@ type DHTNode interface {
 	// Predecessor returns the current predecessor node.
 	Predecessor() *domain.Node
 
-	// HandleLeave processes a leave notification from a node.
-	HandleLeave(leaveNode *domain.Node) error
+	// HandleLeave processes a leave notification sent by a node that is
+	// departing the ring gracefully. See LeaveNotification for the routing
+	// update it carries.
+	HandleLeave(ctx context.Context, notice LeaveNotification) error
 
 	// Notify processes a stabilization notification.
 	Notify(node *domain.Node)
@@ -84,4 +103,36 @@ type DHTNode interface {
 
 	// RoutingMetrics returns live routing statistics for observability.
 	RoutingMetrics() RoutingMetrics
+
+	// Scan walks the ring from start (exclusive) to end (inclusive) —
+	// the same circular semantics as domain.ID.Between — hopping across
+	// successors transparently and streaming matching resources through
+	// the returned ResourceIterator. Building secondary indexes or backup
+	// exports on top of the DHT should use this instead of
+	// GetAllResourceStored, which only covers the local node.
+	Scan(ctx context.Context, start, end domain.ID, opts ScanOptions) (ResourceIterator, error)
+
+	// PrefixScan is a convenience over Scan for the common case of
+	// scanning every resource whose ID shares the given number of
+	// most-significant prefix bits; see PrefixRange for how the
+	// underlying Scan range is derived.
+	PrefixScan(ctx context.Context, prefix []byte, prefixBits int, opts ScanOptions) (ResourceIterator, error)
+
+	// VerifyIntegrity re-checks the locally stored resource at id against
+	// its Adler-32/SHA-256 integrity metadata (see domain.VerifyResource),
+	// for use in background scrub passes that want to detect silent
+	// replica corruption without waiting for a Get to surface it.
+	VerifyIntegrity(id domain.ID) error
+
+	// PutStream stores the resource identified by meta.Key from r without
+	// buffering the whole payload in memory, for objects too large to pass
+	// through Put's domain.Resource.Value. Use this instead of Put for
+	// large blobs; see ResourceMeta for what callers must supply versus
+	// what PutStream derives from the stream itself.
+	PutStream(ctx context.Context, id domain.ID, meta ResourceMeta, r io.Reader) error
+
+	// GetStream retrieves the resource at id as a seekable stream, so
+	// callers serving HTTP range requests don't have to buffer the whole
+	// object just to skip to an offset.
+	GetStream(ctx context.Context, id domain.ID) (ResourceReadSeekCloser, error)
 }
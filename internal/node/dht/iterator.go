@@ -0,0 +1,255 @@
+package dht
+
+import (
+	"KoordeDHT/internal/domain"
+	"sync"
+	"time"
+)
+
+// Iterator represents a sequence of ring nodes discovered from some source
+// (a finger table, a successor list, a static bootstrap list, or a
+// combination of sources). It is modeled on go-ethereum's enode.Iterator:
+// callers repeatedly call Next to advance the iterator and Node to read the
+// current element, and must call Close when done to release resources.
+//
+// Next blocks until a node is available, the iterator is exhausted, or the
+// iterator is closed. It is not safe to call Next concurrently from multiple
+// goroutines.
+type Iterator interface {
+	// Next advances the iterator to the next node. It returns false once the
+	// iterator is exhausted or has been closed.
+	Next() bool
+
+	// Node returns the current node. It is only valid after a call to Next
+	// returned true.
+	Node() *domain.Node
+
+	// Close ends the iterator, unblocking any pending call to Next.
+	Close()
+}
+
+// -------------------------------
+// sliceIterator
+// -------------------------------
+
+// sliceIterator walks a fixed, pre-materialized list of nodes. It backs the
+// finger table, successor list, and bootstrap address iterators, which are
+// all point-in-time snapshots rather than live feeds.
+type sliceIterator struct {
+	mu     sync.Mutex
+	nodes  []*domain.Node
+	cur    *domain.Node
+	pos    int
+	closed bool
+}
+
+// NewSliceIterator returns an Iterator over a fixed slice of nodes, skipping
+// any nil entries (finger tables and successor lists are commonly sparse).
+func NewSliceIterator(nodes []*domain.Node) Iterator {
+	return &sliceIterator{nodes: nodes}
+}
+
+// NewFingerTableIterator wraps a finger-table snapshot as an Iterator.
+func NewFingerTableIterator(fingers []*domain.Node) Iterator {
+	return NewSliceIterator(fingers)
+}
+
+// NewSuccessorListIterator wraps a successor-list snapshot as an Iterator.
+func NewSuccessorListIterator(successors []*domain.Node) Iterator {
+	return NewSliceIterator(successors)
+}
+
+// NewBootstrapIterator resolves a static list of bootstrap addresses into
+// domain.Node entries (with a derived ID in the given space) and wraps them
+// as an Iterator.
+func NewBootstrapIterator(space domain.Space, addrs []string) Iterator {
+	nodes := make([]*domain.Node, 0, len(addrs))
+	for _, addr := range addrs {
+		nodes = append(nodes, &domain.Node{
+			ID:   space.NewIdFromString(addr),
+			Addr: addr,
+		})
+	}
+	return NewSliceIterator(nodes)
+}
+
+func (it *sliceIterator) Next() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	for {
+		if it.closed || it.pos >= len(it.nodes) {
+			it.cur = nil
+			return false
+		}
+		n := it.nodes[it.pos]
+		it.pos++
+		if n != nil {
+			it.cur = n
+			return true
+		}
+	}
+}
+
+func (it *sliceIterator) Node() *domain.Node {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.cur
+}
+
+func (it *sliceIterator) Close() {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	it.closed = true
+}
+
+// -------------------------------
+// fairMix
+// -------------------------------
+
+const (
+	fairMixInitialTimeout = 50 * time.Millisecond
+	fairMixMinTimeout     = 5 * time.Millisecond
+	fairMixMaxTimeout     = 2 * time.Second
+)
+
+// fairMixSource tracks per-source state used to adapt the round-robin
+// timeout: a source that keeps delivering nodes quickly gets a shorter
+// timeout budget, while a source that is stalling gets more time before the
+// mixer moves on, up to fairMixMaxTimeout.
+type fairMixSource struct {
+	it      Iterator
+	timeout time.Duration
+	next    chan *domain.Node
+	done    chan struct{}
+}
+
+// FairMix combines several Iterators, pulling from each sub-iterator in
+// round-robin order. Each source is polled with a dynamic timeout so that a
+// single stuck or slow source cannot starve the others; the timeout shrinks
+// when a source is responsive and grows (up to fairMixMaxTimeout) when it is
+// slow, similar to go-ethereum's discovery fair-mix iterator.
+type FairMix struct {
+	mu      sync.Mutex
+	sources []*fairMixSource
+	cur     *domain.Node
+	closed  chan struct{}
+	closeOn sync.Once
+}
+
+// NewFairMix creates a FairMix over the given sub-iterators.
+func NewFairMix(iters ...Iterator) *FairMix {
+	fm := &FairMix{
+		closed: make(chan struct{}),
+	}
+	for _, it := range iters {
+		fm.AddSource(it)
+	}
+	return fm
+}
+
+// AddSource registers an additional sub-iterator, which is polled in its own
+// goroutine so a blocked source never blocks the mixer's Next call.
+func (fm *FairMix) AddSource(it Iterator) {
+	src := &fairMixSource{
+		it:      it,
+		timeout: fairMixInitialTimeout,
+		next:    make(chan *domain.Node),
+		done:    make(chan struct{}),
+	}
+	go fm.runSource(src)
+
+	fm.mu.Lock()
+	fm.sources = append(fm.sources, src)
+	fm.mu.Unlock()
+}
+
+// runSource feeds discovered nodes from a single sub-iterator into its
+// channel until the sub-iterator is exhausted or the mixer is closed.
+func (fm *FairMix) runSource(src *fairMixSource) {
+	defer close(src.done)
+	for src.it.Next() {
+		select {
+		case src.next <- src.it.Node():
+		case <-fm.closed:
+			return
+		}
+	}
+}
+
+// Next polls the registered sources in round-robin order. Each source gets
+// up to its current timeout to deliver a node; if it misses its window the
+// mixer moves on to the next source and doubles that source's timeout (up to
+// fairMixMaxTimeout). A source that delivers promptly has its timeout halved
+// (down to fairMixMinTimeout) so future rounds spend less time waiting on it.
+func (fm *FairMix) Next() bool {
+	for {
+		fm.mu.Lock()
+		sources := fm.sources
+		fm.mu.Unlock()
+
+		if len(sources) == 0 {
+			fm.cur = nil
+			return false
+		}
+
+		exhausted := 0
+		for _, src := range sources {
+			select {
+			case <-fm.closed:
+				fm.cur = nil
+				return false
+			case n, ok := <-src.next:
+				if !ok {
+					exhausted++
+					continue
+				}
+				src.timeout = halveTimeout(src.timeout)
+				fm.cur = n
+				return true
+			case <-src.done:
+				exhausted++
+			case <-time.After(src.timeout):
+				src.timeout = doubleTimeout(src.timeout)
+			}
+		}
+
+		if exhausted == len(sources) {
+			fm.cur = nil
+			return false
+		}
+	}
+}
+
+func doubleTimeout(d time.Duration) time.Duration {
+	d *= 2
+	if d > fairMixMaxTimeout {
+		d = fairMixMaxTimeout
+	}
+	return d
+}
+
+func halveTimeout(d time.Duration) time.Duration {
+	d /= 2
+	if d < fairMixMinTimeout {
+		d = fairMixMinTimeout
+	}
+	return d
+}
+
+// Node returns the node returned by the most recent successful call to Next.
+func (fm *FairMix) Node() *domain.Node {
+	return fm.cur
+}
+
+// Close shuts down all sub-iterators and unblocks any pending Next call.
+func (fm *FairMix) Close() {
+	fm.closeOn.Do(func() {
+		close(fm.closed)
+	})
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	for _, src := range fm.sources {
+		src.it.Close()
+	}
+}
@@ -0,0 +1,34 @@
+package dht
+
+import "KoordeDHT/internal/domain"
+
+// MembershipManager is an optional capability implemented by DHTNode types
+// whose membership view can be edited directly by an operator, rather than
+// self-healing purely through Join/Leave and stabilization. Simple hash
+// nodes (internal/node/simple) are the motivating case: every node shares
+// the exact same cluster roster, so admitting or evicting a peer is just an
+// update to that shared list. Chord/Koorde nodes figure out their own
+// membership through Join/Leave plus the stabilization protocol instead —
+// the ring self-heals rather than taking external edits — so they
+// intentionally do not implement this interface; callers should type-assert
+// and treat a failed assertion as "not supported for this protocol", not an
+// error.
+type MembershipManager interface {
+	// AddNode admits addr into the cluster's membership view.
+	AddNode(addr string) error
+
+	// RemoveNode evicts addr from the cluster's membership view.
+	RemoveNode(addr string) error
+
+	// ClusterNodes returns every node this instance currently believes is a
+	// member.
+	ClusterNodes() []*domain.Node
+}
+
+// StabilizationAware is implemented by a MembershipManager that can report
+// whether it is mid-stabilization and therefore unsafe to apply a
+// membership edit to right now. Callers that receive true from Stabilizing
+// should retry the edit rather than risk racing a stabilization round.
+type StabilizationAware interface {
+	Stabilizing() bool
+}
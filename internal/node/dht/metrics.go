@@ -3,11 +3,78 @@ package dht
 // RoutingMetrics captures runtime routing statistics that DHT implementations
 // can expose to the HTTP metrics endpoint.
 type RoutingMetrics struct {
-	Protocol                    string  `json:"protocol"`
-	DeBruijnSuccessCount        uint64  `json:"de_bruijn_success"`
-	DeBruijnFailureCount        uint64  `json:"de_bruijn_failures"`
-	SuccessorFallbackCount      uint64  `json:"successor_fallbacks"`
-	AvgDeBruijnSuccessLatencyMs float64 `json:"avg_de_bruijn_success_ms"`
-	AvgDeBruijnFailureLatencyMs float64 `json:"avg_de_bruijn_failure_ms"`
-	AvgSuccessorFallbackLatency float64 `json:"avg_successor_fallback_ms"`
+	Protocol               string `json:"protocol"`
+	DeBruijnSuccessCount   uint64 `json:"de_bruijn_success"`
+	DeBruijnFailureCount   uint64 `json:"de_bruijn_failures"`
+	SuccessorFallbackCount uint64 `json:"successor_fallbacks"`
+
+	// DeBruijnSuccessP50Ms..P999Ms and DeBruijnSuccessBucketCounts
+	// replace the old single AvgDeBruijnSuccessLatencyMs: a mean hides
+	// tail behavior, which is exactly what matters for a cache under
+	// Zipfian load. BucketCounts is the raw histogram (see
+	// logicnode.durationHistogram), in the same bucket order for every
+	// snapshot, so operators can plot the full CDF rather than trusting a
+	// handful of fixed percentiles.
+	DeBruijnSuccessP50Ms         float64  `json:"de_bruijn_success_p50_ms"`
+	DeBruijnSuccessP90Ms         float64  `json:"de_bruijn_success_p90_ms"`
+	DeBruijnSuccessP99Ms         float64  `json:"de_bruijn_success_p99_ms"`
+	DeBruijnSuccessP999Ms        float64  `json:"de_bruijn_success_p999_ms"`
+	DeBruijnSuccessBucketCounts  []uint64 `json:"de_bruijn_success_bucket_counts"`
+	// DeBruijnSuccessHopCounts[i] is the number of successful lookups
+	// that took exactly i de Bruijn hops before resolving (see
+	// logicnode.hopCountBuckets); the last element collapses every hop
+	// count at or beyond its bound into one overflow bucket.
+	DeBruijnSuccessHopCounts []uint64 `json:"de_bruijn_success_hop_counts"`
+
+	DeBruijnFailureP50Ms        float64  `json:"de_bruijn_failure_p50_ms"`
+	DeBruijnFailureP90Ms        float64  `json:"de_bruijn_failure_p90_ms"`
+	DeBruijnFailureP99Ms        float64  `json:"de_bruijn_failure_p99_ms"`
+	DeBruijnFailureP999Ms       float64  `json:"de_bruijn_failure_p999_ms"`
+	DeBruijnFailureBucketCounts []uint64 `json:"de_bruijn_failure_bucket_counts"`
+
+	SuccessorFallbackP50Ms         float64  `json:"successor_fallback_p50_ms"`
+	SuccessorFallbackP90Ms         float64  `json:"successor_fallback_p90_ms"`
+	SuccessorFallbackP99Ms         float64  `json:"successor_fallback_p99_ms"`
+	SuccessorFallbackP999Ms        float64  `json:"successor_fallback_p999_ms"`
+	SuccessorFallbackBucketCounts  []uint64 `json:"successor_fallback_bucket_counts"`
+	// SuccessorFallbackHopCounts[i] is the number of lookups that took
+	// exactly i de Bruijn hops before giving up and falling back to the
+	// successor list.
+	SuccessorFallbackHopCounts []uint64 `json:"successor_fallback_hop_counts"`
+
+	// ChecksumMismatchCount counts resources whose Adler-32/SHA-256
+	// integrity metadata (see domain.VerifyResource) failed to verify on
+	// Get, replica handoff, or a VerifyIntegrity scrub pass, indicating
+	// silent corruption of locally stored data.
+	ChecksumMismatchCount uint64 `json:"checksum_mismatches"`
+
+	// AvgStabilizationRoundMs is the average wall-clock duration, in
+	// milliseconds, of a stabilization round (successor check, notify,
+	// successor-list refresh).
+	AvgStabilizationRoundMs float64 `json:"avg_stabilization_round_ms"`
+
+	// KeyRedistributionCount counts key remaps caused by membership edits.
+	// simple.Node adds one per edit that forced a remap: its hash(key) % N
+	// scheme remaps nearly every key on every AddNode/RemoveNode, unlike
+	// Chord/Koorde's consistent hashing, which only ever remaps the slice
+	// of keyspace that changed hands. rendezvous.Node instead adds the
+	// number of locally stored keys whose HRW owner changed.
+	KeyRedistributionCount uint64 `json:"key_redistributions"`
+
+	// ForwardCount, ForwardFailureCount, and AvgForwardLatencyMs cover
+	// requests a node forwarded to another node it believed responsible,
+	// rather than serving locally. Only simple.Node populates these today:
+	// Chord/Koorde's successor-forwarding is already folded into their
+	// de Bruijn/successor-fallback fields above, but simple.Node had no
+	// forwarding telemetry at all until it gained request forwarding.
+	ForwardCount        uint64  `json:"forward_count"`
+	ForwardFailureCount uint64  `json:"forward_failure_count"`
+	AvgForwardLatencyMs float64 `json:"avg_forward_latency_ms"`
+
+	// HotKeys is the current top-K output of a HotKeyTracker: the
+	// approximately most-frequently-requested keys this node has observed,
+	// with an estimated (never over-, possibly under-) request count each.
+	// Chord and Koorde populate this from every LookUp; simple and
+	// rendezvous leave it empty.
+	HotKeys []HotKey `json:"hot_keys"`
 }
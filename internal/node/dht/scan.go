@@ -0,0 +1,150 @@
+package dht
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// ScanOptions configures a Scan or PrefixScan call.
+type ScanOptions struct {
+	// BatchSize hints how many resources the returned ResourceIterator
+	// should fetch per underlying hop. Implementations may round this up
+	// or down; zero means "use the implementation's default".
+	BatchSize int
+
+	// Cursor resumes a previous Scan/PrefixScan from where it left off, as
+	// returned by ResourceIterator.Cursor(). Empty starts a fresh scan.
+	Cursor string
+}
+
+// ResourceIterator streams the domain.Resource values produced by Scan or
+// PrefixScan. It follows the same Next/Close shape as Iterator, but Next
+// takes a context (a scan spans network hops across the ring, so each step
+// needs its own deadline/cancellation) and exposes Cursor/Err for resumable,
+// fallible traversal.
+type ResourceIterator interface {
+	// Next advances to the next resource in range, hopping across
+	// successors as needed. It returns false once the range is exhausted,
+	// ctx is canceled, or a hop fails — check Err to tell those apart.
+	Next(ctx context.Context) bool
+
+	// Resource returns the resource produced by the most recent successful
+	// call to Next.
+	Resource() domain.Resource
+
+	// Cursor returns an opaque token that resumes this scan immediately
+	// after the last resource returned by Next. Safe to call at any point;
+	// an unstarted iterator returns a cursor equivalent to its starting
+	// ScanOptions.Cursor.
+	Cursor() string
+
+	// Err returns the error, if any, that caused the last Next call to
+	// return false. A nil Err after Next returns false means the range was
+	// simply exhausted.
+	Err() error
+
+	// Close releases resources (open connections) held by the iterator.
+	// Safe to call more than once.
+	Close() error
+}
+
+// ScanCursor is the decoded form of a resumable Scan/PrefixScan token: the
+// ID of the last resource returned and the address of the node that served
+// it, so resuming re-dials that node directly instead of re-routing from the
+// start of the range.
+type ScanCursor struct {
+	LastID   domain.ID
+	ServedBy string
+}
+
+// EncodeScanCursor serializes c into the opaque token used by
+// ScanOptions.Cursor and returned by ResourceIterator.Cursor.
+func EncodeScanCursor(c ScanCursor) string {
+	raw, _ := json.Marshal(struct {
+		LastID   string `json:"last_id"`
+		ServedBy string `json:"served_by"`
+	}{
+		LastID:   c.LastID.ToHexString(false),
+		ServedBy: c.ServedBy,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeScanCursor parses a token produced by EncodeScanCursor. An empty
+// token decodes to the zero ScanCursor with no error, so callers can pass
+// ScanOptions.Cursor through unconditionally.
+func DecodeScanCursor(sp domain.Space, token string) (ScanCursor, error) {
+	if token == "" {
+		return ScanCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ScanCursor{}, fmt.Errorf("dht: invalid scan cursor: %w", err)
+	}
+	var decoded struct {
+		LastID   string `json:"last_id"`
+		ServedBy string `json:"served_by"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return ScanCursor{}, fmt.Errorf("dht: invalid scan cursor: %w", err)
+	}
+	id, err := sp.FromHexString(decoded.LastID)
+	if err != nil {
+		return ScanCursor{}, fmt.Errorf("dht: invalid scan cursor id: %w", err)
+	}
+	return ScanCursor{LastID: id, ServedBy: decoded.ServedBy}, nil
+}
+
+// PrefixRange derives the (start, end] range (in Scan's exclusive-start,
+// inclusive-end sense) of every ID sharing the prefixBits most significant
+// bits of prefix: end is the prefix with every remaining bit set to 1, and
+// start is one less than the prefix with every remaining bit cleared, so
+// that Scan's (start, end] semantics include the whole prefix.
+func PrefixRange(sp domain.Space, prefix []byte, prefixBits int) (start, end domain.ID, err error) {
+	if prefixBits < 0 || prefixBits > sp.Bits {
+		return nil, nil, fmt.Errorf("dht: invalid prefix length %d bits for a %d-bit space", prefixBits, sp.Bits)
+	}
+	requiredBytes := (prefixBits + 7) / 8
+	if len(prefix) < requiredBytes {
+		return nil, nil, fmt.Errorf("dht: prefix is too short for %d bits", prefixBits)
+	}
+
+	base := make(domain.ID, sp.ByteLen)
+	copy(base, prefix[:requiredBytes])
+	if prefixBits%8 != 0 {
+		keep := byte(0xFF << (8 - prefixBits%8))
+		base[requiredBytes-1] &= keep
+	}
+
+	end = make(domain.ID, sp.ByteLen)
+	copy(end, base)
+	for i := prefixBits; i < sp.Bits; i++ {
+		end[i/8] |= 1 << (7 - i%8)
+	}
+
+	extraBits := sp.ByteLen*8 - sp.Bits
+	if extraBits > 0 {
+		mask := byte(0xFF >> extraBits)
+		base[0] &= mask
+		end[0] &= mask
+	}
+
+	maxID := new(big.Int).Lsh(big.NewInt(1), uint(sp.Bits))
+	startBig := new(big.Int).Sub(base.ToBigInt(), big.NewInt(1))
+	if startBig.Sign() < 0 {
+		startBig.Add(startBig, maxID)
+	}
+	start = make(domain.ID, sp.ByteLen)
+	startBytes := startBig.Bytes()
+	copy(start[sp.ByteLen-len(startBytes):], startBytes)
+	if extraBits > 0 {
+		mask := byte(0xFF >> extraBits)
+		start[0] &= mask
+	}
+
+	return start, end, nil
+}
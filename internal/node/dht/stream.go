@@ -0,0 +1,29 @@
+package dht
+
+import (
+	"KoordeDHT/internal/domain"
+	"io"
+)
+
+// ResourceMeta carries a resource's identity and integrity metadata without
+// its payload, for PutStream callers that supply the payload as a stream
+// rather than a single domain.Resource.Value. Checksum and StrongDigest may
+// be left zero when the caller doesn't know them up front (e.g. streaming
+// from an unseekable source); PutStream then derives integrity information
+// from the per-chunk digests it computes as it reads r.
+type ResourceMeta struct {
+	Key domain.ID
+
+	Checksum     uint32
+	StrongDigest []byte
+}
+
+// ResourceReadSeekCloser is returned by GetStream. Seek lets callers serving
+// HTTP range requests, or byte-range video clients, read a subrange of a
+// large object without buffering the whole thing in memory.
+type ResourceReadSeekCloser interface {
+	io.ReadSeekCloser
+
+	// Size returns the total length of the resource in bytes.
+	Size() int64
+}
@@ -0,0 +1,111 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/node/dht"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// durationHistogram is a lock-free log-linear histogram over a
+// time.Duration range, recorded via atomic bucket increments so Observe
+// never blocks a concurrent snapshot (see routingStats.snapshot). The
+// bucket layout itself lives in dht.HistMinNs/HistMaxNs/etc so that
+// internal/metrics.Exporter can reconstruct real bucket boundaries from a
+// RoutingMetrics snapshot's BucketCounts without duplicating this scheme.
+type durationHistogram struct {
+	buckets [dht.HistBucketCount]atomic.Uint64
+}
+
+// observe records d in the bucket covering its nanosecond value.
+// Durations outside the [dht.HistMinNs, dht.HistMaxNs] range land in the
+// underflow/overflow bucket rather than being dropped, so a histogram's
+// total bucket count always matches its number of Observe calls.
+func (h *durationHistogram) observe(d time.Duration) {
+	h.buckets[bucketIndex(d.Nanoseconds())].Add(1)
+}
+
+// bucketIndex maps a nanosecond duration to its bucket in dht's shared
+// layout. Bucket 0 is underflow (<dht.HistMinNs); bucket
+// dht.HistBucketCount-1 is overflow (>=dht.HistMaxNs).
+func bucketIndex(ns int64) int {
+	if ns < dht.HistMinNs {
+		return 0
+	}
+	if ns >= dht.HistMaxNs {
+		return dht.HistBucketCount - 1
+	}
+	octave := math.Log2(float64(ns) / float64(dht.HistMinNs))
+	idx := 1 + int(octave*float64(dht.HistSubBucketsPerOctave))
+	if idx > dht.HistBucketCount-2 {
+		idx = dht.HistBucketCount - 2
+	}
+	return idx
+}
+
+// snapshot copies every bucket count for percentile computation and for
+// exposing the full CDF via RoutingMetrics' BucketCounts fields. Each
+// bucket is read independently via atomic.Load, so a concurrent observe
+// can land just before or after the read without blocking or corrupting
+// either side.
+func (h *durationHistogram) snapshot() []uint64 {
+	out := make([]uint64, len(h.buckets))
+	for i := range h.buckets {
+		out[i] = h.buckets[i].Load()
+	}
+	return out
+}
+
+// percentile returns, in milliseconds, the upper bound of the bucket
+// containing the p-th percentile (0 < p <= 1) of counts. This is an
+// approximation bounded by bucket width rather than exact interpolation
+// between individual observations, the usual precision/memory tradeoff of
+// a fixed-bucket histogram.
+func percentile(counts []uint64, p float64) float64 {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(p * float64(total)))
+	var cum uint64
+	for i, c := range counts {
+		cum += c
+		if cum >= target {
+			return float64(dht.BucketUpperBoundNs(i)) / 1e6
+		}
+	}
+	return float64(dht.HistMaxNs) / 1e6
+}
+
+// hopCountBuckets is a lock-free counter array indexed by de Bruijn hop
+// count, recording how many hops were taken before a lookup resolved
+// (successfully or via successor-list fallback). maxHopBucket is generous
+// relative to any realistic ID-space bit length; hop counts at or beyond
+// it collapse into a single overflow bucket rather than growing the array
+// unboundedly.
+type hopCountBuckets struct {
+	counts [maxHopBucket + 1]atomic.Uint64
+}
+
+const maxHopBucket = 63
+
+func (h *hopCountBuckets) observe(hops int) {
+	if hops < 0 {
+		hops = 0
+	}
+	if hops > maxHopBucket {
+		hops = maxHopBucket
+	}
+	h.counts[hops].Add(1)
+}
+
+func (h *hopCountBuckets) snapshot() []uint64 {
+	out := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		out[i] = h.counts[i].Load()
+	}
+	return out
+}
@@ -1,56 +1,102 @@
 package logicnode
 
 import (
+	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/node/dht"
 	"sync/atomic"
 	"time"
 )
 
-// routingStats tracks Koorde-specific routing instrumentation.
+// routingStats tracks Koorde-specific routing instrumentation: a
+// lock-free latency histogram per outcome (deBruijnSuccess,
+// deBruijnFailure, successorFallback), plus de-Bruijn-hop-count
+// distributions for the two outcomes a hop count applies to (success and
+// fallback — an outright failed hop has no meaningful count of its own).
 type routingStats struct {
 	deBruijnSuccessCount   atomic.Uint64
 	deBruijnFailureCount   atomic.Uint64
 	successorFallbackCount atomic.Uint64
 
-	deBruijnSuccessLatency   atomic.Int64
-	deBruijnFailureLatency   atomic.Int64
-	successorFallbackLatency atomic.Int64
+	deBruijnSuccessLatency   durationHistogram
+	deBruijnFailureLatency   durationHistogram
+	successorFallbackLatency durationHistogram
+
+	deBruijnSuccessHops   hopCountBuckets
+	successorFallbackHops hopCountBuckets
+
+	// hotKeys is a bounded top-K frequency estimator (Misra-Gries, K=1024
+	// by default) over every requested key, feeding RoutingMetrics.HotKeys
+	// so the dashboard has actionable per-key data instead of only
+	// aggregate counters.
+	hotKeys *dht.HotKeyTracker
 }
 
 func newRoutingStats() *routingStats {
-	return &routingStats{}
+	return &routingStats{
+		hotKeys: dht.NewHotKeyTracker(dht.DefaultHotKeyTrackerSize),
+	}
 }
 
-func (s *routingStats) observeDeBruijnSuccess(d time.Duration) {
+// observeRequest records one request for id in the hot-key tracker,
+// independent of which outcome (de Bruijn success/failure/successor
+// fallback) the lookup resolving it eventually takes.
+func (s *routingStats) observeRequest(id domain.ID) {
+	s.hotKeys.Observe(id)
+}
+
+// observeDeBruijnSuccess records a lookup that resolved after hops de
+// Bruijn hops, taking d wall-clock time.
+func (s *routingStats) observeDeBruijnSuccess(d time.Duration, hops int) {
 	s.deBruijnSuccessCount.Add(1)
-	s.deBruijnSuccessLatency.Add(d.Nanoseconds())
+	s.deBruijnSuccessLatency.observe(d)
+	s.deBruijnSuccessHops.observe(hops)
 }
 
 func (s *routingStats) observeDeBruijnFailure(d time.Duration) {
 	s.deBruijnFailureCount.Add(1)
-	s.deBruijnFailureLatency.Add(d.Nanoseconds())
+	s.deBruijnFailureLatency.observe(d)
 }
 
-func (s *routingStats) observeSuccessorFallback(d time.Duration) {
+// observeSuccessorFallback records a lookup that gave up after hops de
+// Bruijn hops and fell back to walking the successor list, taking d
+// wall-clock time overall.
+func (s *routingStats) observeSuccessorFallback(d time.Duration, hops int) {
 	s.successorFallbackCount.Add(1)
-	s.successorFallbackLatency.Add(d.Nanoseconds())
+	s.successorFallbackLatency.observe(d)
+	s.successorFallbackHops.observe(hops)
 }
 
 func (s *routingStats) snapshot() dht.RoutingMetrics {
+	successBuckets := s.deBruijnSuccessLatency.snapshot()
+	failureBuckets := s.deBruijnFailureLatency.snapshot()
+	fallbackBuckets := s.successorFallbackLatency.snapshot()
+
 	return dht.RoutingMetrics{
-		Protocol:                    "koorde",
-		DeBruijnSuccessCount:        s.deBruijnSuccessCount.Load(),
-		DeBruijnFailureCount:        s.deBruijnFailureCount.Load(),
-		SuccessorFallbackCount:      s.successorFallbackCount.Load(),
-		AvgDeBruijnSuccessLatencyMs: avgMillis(s.deBruijnSuccessLatency.Load(), s.deBruijnSuccessCount.Load()),
-		AvgDeBruijnFailureLatencyMs: avgMillis(s.deBruijnFailureLatency.Load(), s.deBruijnFailureCount.Load()),
-		AvgSuccessorFallbackLatency: avgMillis(s.successorFallbackLatency.Load(), s.successorFallbackCount.Load()),
-	}
-}
+		Protocol:               "koorde",
+		DeBruijnSuccessCount:   s.deBruijnSuccessCount.Load(),
+		DeBruijnFailureCount:   s.deBruijnFailureCount.Load(),
+		SuccessorFallbackCount: s.successorFallbackCount.Load(),
+
+		DeBruijnSuccessP50Ms:        percentile(successBuckets, 0.50),
+		DeBruijnSuccessP90Ms:        percentile(successBuckets, 0.90),
+		DeBruijnSuccessP99Ms:        percentile(successBuckets, 0.99),
+		DeBruijnSuccessP999Ms:       percentile(successBuckets, 0.999),
+		DeBruijnSuccessBucketCounts: successBuckets,
+		DeBruijnSuccessHopCounts:    s.deBruijnSuccessHops.snapshot(),
+
+		DeBruijnFailureP50Ms:        percentile(failureBuckets, 0.50),
+		DeBruijnFailureP90Ms:        percentile(failureBuckets, 0.90),
+		DeBruijnFailureP99Ms:        percentile(failureBuckets, 0.99),
+		DeBruijnFailureP999Ms:       percentile(failureBuckets, 0.999),
+		DeBruijnFailureBucketCounts: failureBuckets,
+
+		SuccessorFallbackP50Ms:        percentile(fallbackBuckets, 0.50),
+		SuccessorFallbackP90Ms:        percentile(fallbackBuckets, 0.90),
+		SuccessorFallbackP99Ms:        percentile(fallbackBuckets, 0.99),
+		SuccessorFallbackP999Ms:       percentile(fallbackBuckets, 0.999),
+		SuccessorFallbackBucketCounts: fallbackBuckets,
+		SuccessorFallbackHopCounts:    s.successorFallbackHops.snapshot(),
 
-func avgMillis(totalNano int64, count uint64) float64 {
-	if count == 0 {
-		return 0
+		HotKeys: s.hotKeys.TopK(),
 	}
-	return float64(totalNano) / float64(count) / 1e6
 }
@@ -0,0 +1,242 @@
+// Package origin fetches content from upstream origin servers on behalf of
+// the HTTP cache server, owning the outbound transport(s) used to do so.
+package origin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// envForwardProxyIP, if set, forces ALL egress through a single local
+// recorder regardless of Config, mirroring etcd's E2E_TEST_FORWARD_PROXY_IP
+// hook so integration tests can observe/replay origin traffic without
+// threading a test-only code path through Config.
+const envForwardProxyIP = "KOORDE_E2E_TEST_FORWARD_PROXY_IP"
+
+const defaultTimeout = 30 * time.Second
+const maxRedirects = 10
+
+// Response is what a Fetch call learned from the origin, including the
+// RFC 7234 validator/directive headers needed by the caller to decide
+// caching and revalidation (left unparsed here — that's the cache
+// package's concern, not transport's).
+type Response struct {
+	Content      []byte
+	ContentType  string
+	StatusCode   int
+	ETag         string
+	LastModified string
+	Expires      string
+	CacheControl string
+}
+
+// Rule routes origin fetches whose URL matches Pattern through their own
+// Transport, with their own timeout, body-size cap, and TLS config —
+// e.g. an image CDN pull needing a longer timeout and bigger body cap than
+// a JSON API origin needing a short, strict one.
+type Rule struct {
+	// Name identifies the rule in logs/errors.
+	Name string
+
+	// Pattern selects which origin URLs this rule applies to. It's tried
+	// as a regexp first (matched against the full URL); if it fails to
+	// compile, it's treated as a "*"-wildcard glob instead (e.g.
+	// "*.images.example.com"), matched against the full URL too. Patterns
+	// that are valid as both (e.g. containing a bare "-*") are matched as
+	// a regexp — prefer an unambiguous glob like "*.example.com" or an
+	// anchored regexp like "^https://api\\." when that matters.
+	Pattern string
+
+	// ProxyURL, if set, routes this rule's egress through the given proxy
+	// instead of Config.ProxyFunc/the environment.
+	ProxyURL string
+
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	TLSConfig    *tls.Config
+
+	matcher func(rawURL string) bool
+	client  *http.Client
+}
+
+// Config configures a Fetcher.
+type Config struct {
+	// DefaultTimeout is used by requests that match no Rule. Defaults to
+	// 30s.
+	DefaultTimeout time.Duration
+
+	// DefaultMaxBodyBytes caps response bodies fetched via the default
+	// transport; 0 means unlimited.
+	DefaultMaxBodyBytes int64
+
+	// ProxyFunc overrides how the default transport (and any Rule without
+	// its own ProxyURL) picks a proxy for a request. Defaults to
+	// http.ProxyFromEnvironment.
+	ProxyFunc func(*http.Request) (*url.URL, error)
+
+	// Rules are evaluated in order; the first match wins. Requests
+	// matching none use the default transport.
+	Rules []Rule
+}
+
+// Fetcher performs origin GETs, selecting a Rule's transport/timeout/body
+// cap when the request URL matches one, and falling back to a default
+// transport (honoring http.ProxyFromEnvironment unless overridden)
+// otherwise.
+type Fetcher struct {
+	defaultClient       *http.Client
+	defaultMaxBodyBytes int64
+	rules               []Rule
+}
+
+// NewFetcher builds a Fetcher from cfg, compiling each Rule's Pattern.
+// Returns an error if any Rule's Pattern or ProxyURL is invalid.
+func NewFetcher(cfg Config) (*Fetcher, error) {
+	proxyFunc := cfg.ProxyFunc
+	if forced := os.Getenv(envForwardProxyIP); forced != "" {
+		forcedURL := &url.URL{Scheme: "http", Host: forced}
+		proxyFunc = func(*http.Request) (*url.URL, error) { return forcedURL, nil }
+	} else if proxyFunc == nil {
+		proxyFunc = http.ProxyFromEnvironment
+	}
+
+	timeout := cfg.DefaultTimeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	f := &Fetcher{
+		defaultClient: &http.Client{
+			Timeout:       timeout,
+			Transport:     &http.Transport{Proxy: proxyFunc},
+			CheckRedirect: limitRedirects,
+		},
+		defaultMaxBodyBytes: cfg.DefaultMaxBodyBytes,
+	}
+
+	for _, rule := range cfg.Rules {
+		matcher, err := compileMatcher(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("origin: rule %q: invalid pattern %q: %w", rule.Name, rule.Pattern, err)
+		}
+		rule.matcher = matcher
+
+		transport := &http.Transport{Proxy: proxyFunc}
+		if rule.ProxyURL != "" {
+			proxyURL, err := url.Parse(rule.ProxyURL)
+			if err != nil {
+				return nil, fmt.Errorf("origin: rule %q: invalid proxy URL %q: %w", rule.Name, rule.ProxyURL, err)
+			}
+			if os.Getenv(envForwardProxyIP) == "" {
+				transport.Proxy = http.ProxyURL(proxyURL)
+			}
+		}
+		if rule.TLSConfig != nil {
+			transport.TLSClientConfig = rule.TLSConfig
+		}
+
+		ruleTimeout := rule.Timeout
+		if ruleTimeout <= 0 {
+			ruleTimeout = timeout
+		}
+		rule.client = &http.Client{
+			Timeout:       ruleTimeout,
+			Transport:     transport,
+			CheckRedirect: limitRedirects,
+		}
+
+		f.rules = append(f.rules, rule)
+	}
+
+	return f, nil
+}
+
+func limitRedirects(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("too many redirects")
+	}
+	return nil
+}
+
+// clientFor returns the transport (and body-size cap) that applies to
+// rawURL: the first matching Rule's, or the Fetcher's default.
+func (f *Fetcher) clientFor(rawURL string) (*http.Client, int64) {
+	for _, rule := range f.rules {
+		if rule.matcher(rawURL) {
+			return rule.client, rule.MaxBodyBytes
+		}
+	}
+	return f.defaultClient, f.defaultMaxBodyBytes
+}
+
+// Fetch performs a conditional-or-plain GET for rawURL. If etag or
+// lastModified is non-empty, the matching conditional header
+// (If-None-Match / If-Modified-Since) is sent; a 304 response is reported
+// via the notModified return with Content left nil.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL, etag, lastModified string) (*Response, bool, error) {
+	client, maxBodyBytes := f.clientFor(rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("origin: failed to build request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("origin: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &Response{
+			StatusCode:   resp.StatusCode,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      resp.Header.Get("Expires"),
+			CacheControl: resp.Header.Get("Cache-Control"),
+		}, true, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("origin: status %d", resp.StatusCode)
+	}
+
+	var body io.Reader = resp.Body
+	if maxBodyBytes > 0 {
+		body = io.LimitReader(resp.Body, maxBodyBytes+1)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("origin: failed to read response: %w", err)
+	}
+	if maxBodyBytes > 0 && int64(len(content)) > maxBodyBytes {
+		return nil, false, fmt.Errorf("origin: response exceeds max body size of %d bytes", maxBodyBytes)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &Response{
+		Content:      content,
+		ContentType:  contentType,
+		StatusCode:   resp.StatusCode,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Expires:      resp.Header.Get("Expires"),
+		CacheControl: resp.Header.Get("Cache-Control"),
+	}, false, nil
+}
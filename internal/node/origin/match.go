@@ -0,0 +1,38 @@
+package origin
+
+import (
+	"regexp"
+	"strings"
+)
+
+// compileMatcher turns a Rule.Pattern into a matcher function, trying it
+// as a regexp first and falling back to "*"-wildcard glob semantics.
+func compileMatcher(pattern string) (func(string) bool, error) {
+	if re, err := regexp.Compile(pattern); err == nil {
+		return re.MatchString, nil
+	}
+
+	re, err := regexp.Compile(globToRegexPattern(pattern))
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
+
+// globToRegexPattern converts a "*"-wildcard glob (e.g.
+// "*.images.example.com") into an equivalent anchored regexp pattern,
+// escaping every other regexp metacharacter so the glob is matched
+// literally.
+func globToRegexPattern(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		if r == '*' {
+			b.WriteString(".*")
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,594 @@
+// Package rendezvous provides a Highest-Random-Weight (HRW) hashing-based
+// "DHT" implementation for baseline comparison experiments.
+//
+// Like simple, membership is a flat, statically-agreed list rather than a
+// ring, so there is no routing to speak of: ownership of a key is decided
+// directly from the membership list. Unlike simple's hash(key) % N,
+// ownership here is decided by computing weight(key, node) = hash(key ||
+// node.Addr) for every node and picking the maximum. HRW's key property is
+// that only ~1/N of keys change owner when a node is added or removed
+// (every key whose new winner is the changed node, or whose old winner was
+// the removed node), instead of simple's ~100% churn — giving a fair
+// midpoint between the two baselines and Koorde/Chord for the
+// workload-generator experiments.
+package rendezvous
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/dht"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Node implements a Rendezvous (HRW) hashing-based node for baseline
+// comparison. It does not perform any DHT routing - instead it uses HRW
+// hashing over the full membership list to determine which node is
+// responsible for a key.
+type Node struct {
+	lgr   logger.Logger
+	s     *storage.Storage
+	cp    *client2.Pool // client pool (for API compatibility, not used for routing)
+	space domain.Space
+
+	mu           sync.RWMutex
+	self         *domain.Node   // This node's identity
+	clusterNodes []*domain.Node // All nodes in the cluster (sorted by address)
+	nodeIndex    int            // This node's index in the sorted cluster list
+
+	checksumMismatches atomic.Uint64
+
+	// keyReassignments accumulates, across every AddNode/RemoveNode call,
+	// the number of locally stored keys whose HRW winner changed as a
+	// result — surfaced via RoutingMetrics.KeyRedistributionCount. Unlike
+	// simple.Node's keyRedistributions (one per membership edit, since
+	// every edit remaps ~100% of keys), this counts actual keys moved,
+	// since HRW only remaps a fraction of them.
+	keyReassignments atomic.Uint64
+}
+
+// New creates a new rendezvous (HRW) hash node.
+//
+// Parameters:
+//   - self: this node's identity (ID and address)
+//   - space: the identifier space configuration
+//   - cp: client pool (for API compatibility)
+//   - storage: the local storage for this node
+//   - opts: optional configuration options
+func New(self *domain.Node, space domain.Space, cp *client2.Pool, storage *storage.Storage, opts ...Option) *Node {
+	n := &Node{
+		lgr:          &logger.NopLogger{},
+		s:            storage,
+		cp:           cp,
+		space:        space,
+		self:         self,
+		clusterNodes: []*domain.Node{self}, // Initially just self
+		nodeIndex:    0,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// SetClusterNodes sets the full list of cluster nodes.
+// This should be called after New() with the complete cluster membership.
+func (n *Node) SetClusterNodes(nodes []*domain.Node) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Sort nodes by their address (for deterministic ordering)
+	sorted := make([]*domain.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Addr < sorted[j].Addr
+	})
+
+	n.clusterNodes = sorted
+
+	// Find our index in the sorted list
+	for i, node := range n.clusterNodes {
+		if node.Addr == n.self.Addr {
+			n.nodeIndex = i
+			break
+		}
+	}
+
+	n.lgr.Info("rendezvous: cluster nodes set",
+		logger.F("total_nodes", len(n.clusterNodes)),
+		logger.F("self_index", n.nodeIndex),
+		logger.FNode("self", n.self))
+}
+
+// hrwWeight computes the HRW weight of (id, addr) as sha256(id || addr),
+// interpreted as a big-endian unsigned integer. Using a cryptographic hash
+// here (rather than a cheaper checksum) keeps weights uniformly and
+// independently distributed per (id, node) pair, which is what guarantees
+// HRW's even load spread and its ~1/N churn property on membership change.
+func hrwWeight(id domain.ID, addr string) *big.Int {
+	buf := make([]byte, 0, len(id)+len(addr))
+	buf = append(buf, id...)
+	buf = append(buf, addr...)
+	sum := sha256.Sum256(buf)
+	return new(big.Int).SetBytes(sum[:])
+}
+
+// getResponsibleNodeLocked returns the node with the highest HRW weight for
+// id among nodes. Caller must hold n.mu (read or write).
+func getResponsibleNodeLocked(id domain.ID, nodes []*domain.Node) *domain.Node {
+	var winner *domain.Node
+	var winnerWeight *big.Int
+	for _, node := range nodes {
+		w := hrwWeight(id, node.Addr)
+		if winnerWeight == nil || w.Cmp(winnerWeight) > 0 {
+			winner = node
+			winnerWeight = w
+		}
+	}
+	return winner
+}
+
+// getResponsibleNode returns the node responsible for the given ID under
+// the current membership.
+func (n *Node) getResponsibleNode(id domain.ID) *domain.Node {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if len(n.clusterNodes) == 0 {
+		return n.self
+	}
+	return getResponsibleNodeLocked(id, n.clusterNodes)
+}
+
+// isResponsible returns true if this node is responsible for the given ID.
+func (n *Node) isResponsible(id domain.ID) bool {
+	responsible := n.getResponsibleNode(id)
+	return responsible != nil && responsible.Addr == n.self.Addr
+}
+
+// IsResponsibleFor checks if this node is responsible for the given ID.
+// This is the PUBLIC version for use by the HTTP server's ownership check.
+// It uses HRW-based ownership: the node with the maximum weight(id, addr)
+// among the current cluster membership.
+func (n *Node) IsResponsibleFor(id domain.ID) bool {
+	return n.isResponsible(id)
+}
+
+// Join is a no-op for rendezvous nodes since membership is static.
+// The cluster is fully defined at construction time.
+func (n *Node) Join(peers []string) error {
+	n.lgr.Info("rendezvous: Join called (no-op for static membership)")
+	return nil
+}
+
+// Leave is a no-op for rendezvous nodes.
+func (n *Node) Leave() error {
+	n.lgr.Info("rendezvous: Leave called")
+	return nil
+}
+
+// Stop releases resources.
+func (n *Node) Stop() {
+	n.lgr.Info("rendezvous: node stopped")
+}
+
+// Put stores a resource. For rendezvous, it stores locally if we're responsible.
+func (n *Node) Put(ctx context.Context, res domain.Resource) error {
+	res.Checksum, res.StrongDigest = domain.ChecksumResource(res.Value, true)
+	if n.isResponsible(res.Key) {
+		return n.StoreLocal(ctx, res)
+	}
+	// In a full implementation, we would forward to the responsible node
+	// For now, we just return an error (the HTTP layer handles forwarding)
+	return fmt.Errorf("rendezvous: not responsible for key %s", res.Key.ToHexString(true))
+}
+
+// Get retrieves a resource. For rendezvous, it retrieves locally if we're responsible.
+func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error) {
+	if n.isResponsible(id) {
+		res, err := n.RetrieveLocal(id)
+		if err != nil {
+			return nil, err
+		}
+		return &res, nil
+	}
+	return nil, fmt.Errorf("rendezvous: not responsible for key %s", id.ToHexString(true))
+}
+
+// Delete removes a resource.
+func (n *Node) Delete(ctx context.Context, id domain.ID) error {
+	if n.isResponsible(id) {
+		return n.RemoveLocal(id)
+	}
+	return fmt.Errorf("rendezvous: not responsible for key %s", id.ToHexString(true))
+}
+
+// PutStream stores the resource at id from r without buffering the whole
+// payload in memory. As with Put, rendezvous does not forward to the
+// responsible node — it either stores locally or returns an error.
+func (n *Node) PutStream(ctx context.Context, id domain.ID, meta dht.ResourceMeta, r io.Reader) error {
+	if n.isResponsible(id) {
+		return n.s.PutStream(id, meta, r)
+	}
+	return fmt.Errorf("rendezvous: not responsible for key %s", id.ToHexString(true))
+}
+
+// GetStream retrieves the resource at id as a seekable stream.
+func (n *Node) GetStream(ctx context.Context, id domain.ID) (dht.ResourceReadSeekCloser, error) {
+	if n.isResponsible(id) {
+		return n.s.GetStream(id)
+	}
+	return nil, fmt.Errorf("rendezvous: not responsible for key %s", id.ToHexString(true))
+}
+
+// LookUp finds the node responsible for the given ID using HRW hashing.
+func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
+	responsible := n.getResponsibleNode(id)
+	n.lgr.Debug("rendezvous: lookup",
+		logger.F("key", id.ToHexString(true)),
+		logger.FNode("responsible", responsible))
+	return responsible, nil
+}
+
+// HandleFindSuccessor processes a FindSuccessor RPC request.
+// For rendezvous, this just returns the responsible node based on HRW weight.
+func (n *Node) HandleFindSuccessor(ctx context.Context, req *dhtv1.FindSuccessorRequest) (*dhtv1.FindSuccessorResponse, error) {
+	if req == nil || len(req.TargetId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing target_id")
+	}
+
+	target := domain.ID(req.TargetId)
+	responsible := n.getResponsibleNode(target)
+
+	return &dhtv1.FindSuccessorResponse{
+		Node: responsible.ToProtoDHT(),
+	}, nil
+}
+
+// Self returns this node's identity.
+func (n *Node) Self() *domain.Node {
+	return n.self
+}
+
+// SuccessorList returns all other nodes in the cluster (for compatibility).
+// Rendezvous doesn't use successor lists, but we return all nodes for
+// the HTTP layer's fallback logic.
+func (n *Node) SuccessorList() []*domain.Node {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	result := make([]*domain.Node, 0, len(n.clusterNodes))
+	for _, node := range n.clusterNodes {
+		if node.Addr != n.self.Addr {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// DeBruijnList returns nil - rendezvous doesn't use de Bruijn routing.
+func (n *Node) DeBruijnList() []*domain.Node {
+	return nil
+}
+
+// Predecessor returns nil - rendezvous doesn't use predecessor pointers.
+func (n *Node) Predecessor() *domain.Node {
+	// For rendezvous, we can compute our "predecessor" as the node before us in the sorted list
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if len(n.clusterNodes) <= 1 {
+		return nil
+	}
+
+	predIdx := (n.nodeIndex - 1 + len(n.clusterNodes)) % len(n.clusterNodes)
+	return n.clusterNodes[predIdx]
+}
+
+// HandleLeave processes a leave notification (no-op for rendezvous, since
+// membership here is static and managed via AddNode/RemoveNode instead).
+func (n *Node) HandleLeave(ctx context.Context, notice dht.LeaveNotification) error {
+	return nil
+}
+
+// Notify processes a stabilization notification (no-op for rendezvous).
+func (n *Node) Notify(node *domain.Node) {
+	// No-op - rendezvous doesn't use stabilization
+}
+
+// IsValidID checks if the given ID is valid for this node's space.
+func (n *Node) IsValidID(id []byte) error {
+	return n.space.IsValidID(id)
+}
+
+// Space returns the identifier space configuration.
+func (n *Node) Space() *domain.Space {
+	return &n.space
+}
+
+// EstimateNetworkSize returns the known cluster size.
+func (n *Node) EstimateNetworkSize() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.clusterNodes)
+}
+
+// GetAllResourceStored returns all resources stored locally.
+func (n *Node) GetAllResourceStored() []domain.Resource {
+	return n.s.All()
+}
+
+// StoreLocal stores a resource locally, verifying its integrity metadata first.
+func (n *Node) StoreLocal(ctx context.Context, res domain.Resource) error {
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("StoreLocal: integrity check failed", logger.F("key", res.Key.ToHexString(true)), logger.F("err", err))
+		return err
+	}
+	n.s.Put(res)
+	return nil
+}
+
+// RetrieveLocal retrieves a resource locally, verifying its integrity
+// metadata before returning it.
+func (n *Node) RetrieveLocal(id domain.ID) (domain.Resource, error) {
+	res, err := n.s.Get(id)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("RetrieveLocal: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return domain.Resource{}, err
+	}
+	return res, nil
+}
+
+// RemoveLocal removes a resource locally.
+func (n *Node) RemoveLocal(id domain.ID) error {
+	return n.s.Delete(id)
+}
+
+// VerifyIntegrity re-checks the locally stored resource at id against its
+// integrity metadata, for use by background scrub passes.
+func (n *Node) VerifyIntegrity(id domain.ID) error {
+	res, err := n.s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("VerifyIntegrity: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return err
+	}
+	return nil
+}
+
+// CreateNewDHT initializes the node (no-op for rendezvous - already initialized).
+func (n *Node) CreateNewDHT() {
+	n.lgr.Info("rendezvous: CreateNewDHT called (cluster already initialized)")
+}
+
+// StartStabilizers starts background tasks (no-op for rendezvous).
+// Rendezvous doesn't need stabilization since membership is static.
+func (n *Node) StartStabilizers(ctx context.Context, stabilizationInterval, deBruijnInterval, storageInterval time.Duration) {
+	n.lgr.Info("rendezvous: stabilizers not needed for static membership")
+}
+
+// RoutingMetrics returns routing statistics.
+func (n *Node) RoutingMetrics() dht.RoutingMetrics {
+	return dht.RoutingMetrics{
+		Protocol:               "rendezvous",
+		ChecksumMismatchCount:  n.checksumMismatches.Load(),
+		KeyRedistributionCount: n.keyReassignments.Load(),
+	}
+}
+
+// ClusterNodes returns all nodes in the cluster (for debugging/metrics).
+func (n *Node) ClusterNodes() []*domain.Node {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	result := make([]*domain.Node, len(n.clusterNodes))
+	copy(result, n.clusterNodes)
+	return result
+}
+
+// countReassignments returns how many of the locally stored resources would
+// change HRW winner between oldCluster and newCluster. This is an O(N*K)
+// re-run of HRW (N = cluster size, K = locally stored keys) rather than a
+// cluster-wide figure, since a node only has visibility into the keys it
+// currently stores — but for the workload-generator experiments this is
+// computed here, not estimated, on every membership edit.
+func countReassignments(oldCluster, newCluster []*domain.Node, resources []domain.Resource) uint64 {
+	var changed uint64
+	for _, res := range resources {
+		oldWinner := getResponsibleNodeLocked(res.Key, oldCluster)
+		newWinner := getResponsibleNodeLocked(res.Key, newCluster)
+		if oldWinner == nil || newWinner == nil || oldWinner.Addr != newWinner.Addr {
+			changed++
+		}
+	}
+	return changed
+}
+
+// RemoveNode removes a node from the cluster membership.
+// This is used to update membership when a node leaves or fails.
+func (n *Node) RemoveNode(addr string) error {
+	n.mu.Lock()
+
+	// Find and remove the node with the given address
+	newCluster := make([]*domain.Node, 0, len(n.clusterNodes)-1)
+	found := false
+	for _, node := range n.clusterNodes {
+		if node.Addr == addr {
+			found = true
+			continue
+		}
+		newCluster = append(newCluster, node)
+	}
+
+	if !found {
+		n.mu.Unlock()
+		return fmt.Errorf("rendezvous: node %s not found in cluster", addr)
+	}
+
+	oldCluster := n.clusterNodes
+	n.clusterNodes = newCluster
+
+	// Recalculate our own index in the new cluster
+	for i, node := range n.clusterNodes {
+		if node.Addr == n.self.Addr {
+			n.nodeIndex = i
+			break
+		}
+	}
+	n.mu.Unlock()
+
+	n.keyReassignments.Add(countReassignments(oldCluster, newCluster, n.GetAllResourceStored()))
+
+	n.lgr.Info("rendezvous: node removed from cluster",
+		logger.F("removed_addr", addr),
+		logger.F("new_cluster_size", len(newCluster)),
+		logger.F("self_index", n.nodeIndex))
+
+	return nil
+}
+
+// Scan walks the range (start, end] using only this node's local storage:
+// rendezvous hashing has no ring ordering to hop across (ownership is
+// the HRW winner, not range-based), so unlike chord.Node.Scan it cannot
+// visit other nodes' resources. Callers that need a cluster-wide scan must
+// issue Scan against every node and merge the results themselves.
+func (n *Node) Scan(ctx context.Context, start, end domain.ID, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	low := start
+	if opts.Cursor != "" {
+		cursor, err := dht.DecodeScanCursor(n.space, opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		low = cursor.LastID
+	}
+
+	var matches []domain.Resource
+	for _, res := range n.GetAllResourceStored() {
+		if res.Key.Between(low, end) {
+			matches = append(matches, res)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Key.Cmp(matches[j].Key) < 0 })
+
+	return &localScanIterator{self: n.self, matches: matches, start: low}, nil
+}
+
+// PrefixScan derives a (start, end] range from prefix/prefixBits via
+// dht.PrefixRange and delegates to Scan.
+func (n *Node) PrefixScan(ctx context.Context, prefix []byte, prefixBits int, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	start, end, err := dht.PrefixRange(n.space, prefix, prefixBits)
+	if err != nil {
+		return nil, err
+	}
+	return n.Scan(ctx, start, end, opts)
+}
+
+// localScanIterator implements dht.ResourceIterator over a pre-materialized,
+// already-sorted slice of locally stored resources.
+type localScanIterator struct {
+	self    *domain.Node
+	matches []domain.Resource
+	pos     int
+	cur     domain.Resource
+	start   domain.ID // lower bound the scan began from, for Cursor before Next
+}
+
+func (it *localScanIterator) Next(ctx context.Context) bool {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return false
+	}
+	if it.pos >= len(it.matches) {
+		return false
+	}
+	it.cur = it.matches[it.pos]
+	it.pos++
+	return true
+}
+
+func (it *localScanIterator) Resource() domain.Resource { return it.cur }
+
+func (it *localScanIterator) Cursor() string {
+	last := it.cur.Key
+	if it.pos == 0 {
+		last = it.start
+	}
+	return dht.EncodeScanCursor(dht.ScanCursor{LastID: last, ServedBy: it.self.Addr})
+}
+
+func (it *localScanIterator) Err() error { return nil }
+
+func (it *localScanIterator) Close() error { return nil }
+
+// AddNode adds a node to the cluster membership.
+// This is used to update membership when a new node joins.
+func (n *Node) AddNode(addr string) error {
+	n.mu.Lock()
+
+	// Check if node already exists
+	for _, node := range n.clusterNodes {
+		if node.Addr == addr {
+			n.lgr.Debug("rendezvous: node already in cluster",
+				logger.F("addr", addr))
+			n.mu.Unlock()
+			return nil // Already exists, no-op
+		}
+	}
+
+	// Create a new node with a generated ID based on address
+	newNodeID := n.space.NewIdFromString(addr)
+	newNode := &domain.Node{
+		ID:   newNodeID,
+		Addr: addr,
+	}
+
+	oldCluster := n.clusterNodes
+
+	// Add to cluster and re-sort
+	newCluster := append(append([]*domain.Node{}, n.clusterNodes...), newNode)
+	sort.Slice(newCluster, func(i, j int) bool {
+		return newCluster[i].Addr < newCluster[j].Addr
+	})
+	n.clusterNodes = newCluster
+
+	// Recalculate our own index in the new cluster
+	for i, node := range n.clusterNodes {
+		if node.Addr == n.self.Addr {
+			n.nodeIndex = i
+			break
+		}
+	}
+	n.mu.Unlock()
+
+	n.keyReassignments.Add(countReassignments(oldCluster, newCluster, n.GetAllResourceStored()))
+
+	n.lgr.Info("rendezvous: node added to cluster",
+		logger.F("added_addr", addr),
+		logger.F("new_cluster_size", len(newCluster)),
+		logger.F("self_index", n.nodeIndex))
+
+	return nil
+}
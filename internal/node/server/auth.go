@@ -0,0 +1,255 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Role is the permission level assigned to an authenticated Principal.
+// There are currently only two tiers: Read, for callers that merely
+// observe state, and Admin, for callers allowed to mutate it (cluster
+// membership edits today; more as admin surface grows).
+type Role string
+
+const (
+	RoleRead  Role = "read"
+	RoleAdmin Role = "admin"
+)
+
+// satisfies reports whether r meets the minimum role required by a route's
+// ACL entry. Admin satisfies both Read and Admin; Read satisfies only Read.
+func (r Role) satisfies(min Role) bool {
+	if min == RoleRead {
+		return true
+	}
+	return r == RoleAdmin
+}
+
+// Principal identifies the caller an Authenticator resolved a request to.
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// ErrNoCredentials and ErrInvalidCredentials are the two ways
+// Authenticate can fail: no credentials were presented at all, versus
+// credentials that were presented but didn't check out. Both map to 401
+// in requireRole; the distinction exists for logging, not response shape.
+var (
+	ErrNoCredentials      = errors.New("no credentials presented")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator resolves the caller of an HTTP request to a Principal,
+// patterned on rqlite's auth.CredentialStore: pluggable so HTTPCacheServer
+// doesn't hard-code a single credential scheme. BasicAuthenticator,
+// BearerTokenAuthenticator, and MTLSAuthenticator below are the three
+// built-in schemes; ChainAuthenticator composes any combination of them so
+// a deployment can accept more than one at once.
+type Authenticator interface {
+	// Authenticate resolves r's caller, or returns ErrNoCredentials /
+	// ErrInvalidCredentials (or a wrapping error) if it can't.
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// BasicAuthenticator authenticates via RFC 7617 HTTP Basic Auth against an
+// in-memory username/password/role table.
+type BasicAuthenticator struct {
+	users map[string]basicCredential
+}
+
+type basicCredential struct {
+	password string
+	role     Role
+}
+
+// NewBasicAuthenticator builds a BasicAuthenticator from username ->
+// (password, role) entries, typically produced by LoadAdminUsers.
+func NewBasicAuthenticator(users map[string]basicCredential) *BasicAuthenticator {
+	return &BasicAuthenticator{users: users}
+}
+
+// dummyPassword stands in for cred.password when user isn't found, so
+// Authenticate always pays the same ConstantTimeCompare cost and an
+// unknown username can't be distinguished from a wrong password by timing.
+const dummyPassword = "$dummy$password$for$constant$time$compare$"
+
+func (a *BasicAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	cred, found := a.users[user]
+	want := dummyPassword
+	if found {
+		want = cred.password
+	}
+	// Constant-time compare, unconditionally, so a timing side-channel
+	// can't be used to learn a valid password byte-by-byte or to learn
+	// whether user exists at all.
+	match := subtle.ConstantTimeCompare([]byte(want), []byte(pass)) == 1
+	if !found || !match {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Name: user, Role: cred.role}, nil
+}
+
+// BearerTokenAuthenticator authenticates via an "Authorization: Bearer
+// <token>" header against an in-memory token/name/role table.
+type BearerTokenAuthenticator struct {
+	tokens map[string]Principal
+}
+
+// NewBearerTokenAuthenticator builds a BearerTokenAuthenticator from token
+// -> Principal entries, typically produced by LoadAdminUsers.
+func NewBearerTokenAuthenticator(tokens map[string]Principal) *BearerTokenAuthenticator {
+	return &BearerTokenAuthenticator{tokens: tokens}
+}
+
+func (a *BearerTokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if len(h) <= len(prefix) || h[:len(prefix)] != prefix {
+		return nil, ErrNoCredentials
+	}
+	token := h[len(prefix):]
+	for known, p := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			principal := p
+			return &principal, nil
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// MTLSAuthenticator authenticates by the Common Name of the client
+// certificate presented during the TLS handshake, against an allow-list of
+// CN -> Principal entries. Requires the server's tls.Config to set
+// ClientAuth to tls.RequireAndVerifyClientCert or similar; this type only
+// reads r.TLS.PeerCertificates, it doesn't configure the listener.
+type MTLSAuthenticator struct {
+	cns map[string]Principal
+}
+
+// NewMTLSAuthenticator builds an MTLSAuthenticator from client-certificate
+// CN -> Principal entries, typically produced by LoadAdminUsers.
+func NewMTLSAuthenticator(cns map[string]Principal) *MTLSAuthenticator {
+	return &MTLSAuthenticator{cns: cns}
+}
+
+func (a *MTLSAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, ErrNoCredentials
+	}
+	cn := r.TLS.PeerCertificates[0].Subject.CommonName
+	p, ok := a.cns[cn]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	principal := p
+	return &principal, nil
+}
+
+// ChainAuthenticator tries each Authenticator in order and returns the
+// first successful resolution, so a deployment can accept e.g. both basic
+// auth and bearer tokens at once. Authenticate returns ErrNoCredentials
+// only if every link saw no credentials at all; if at least one link saw
+// credentials but rejected them, it returns that link's error instead, so
+// a typo'd password doesn't get silently swallowed as "no credentials".
+type ChainAuthenticator []Authenticator
+
+func (c ChainAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	err := error(ErrNoCredentials)
+	for _, a := range c {
+		p, aerr := a.Authenticate(r)
+		if aerr == nil {
+			return p, nil
+		}
+		if !errors.Is(aerr, ErrNoCredentials) {
+			err = aerr
+		}
+	}
+	return nil, err
+}
+
+// AdminUser is one entry in an --admin-users credentials file: exactly one
+// of Password, Token, or CertCN should be set, selecting which
+// Authenticator scheme the entry participates in.
+type AdminUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	CertCN   string `json:"cert_cn,omitempty"`
+	Role     Role   `json:"role"`
+}
+
+// LoadAdminUsersFile reads a JSON array of AdminUser from path, the file
+// pointed to by the node binary's --admin-users flag.
+func LoadAdminUsersFile(path string) ([]AdminUser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load admin users: %w", err)
+	}
+	return parseAdminUsers(data)
+}
+
+// LoadAdminUsersEnv reads the same JSON array of AdminUser from the
+// environment variable named by key, for deployments that prefer passing
+// credentials through their secret-injection mechanism over a file on
+// disk.
+func LoadAdminUsersEnv(key string) ([]AdminUser, error) {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return nil, fmt.Errorf("load admin users: environment variable %q not set", key)
+	}
+	return parseAdminUsers([]byte(raw))
+}
+
+func parseAdminUsers(data []byte) ([]AdminUser, error) {
+	var users []AdminUser
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("load admin users: %w", err)
+	}
+	return users, nil
+}
+
+// NewAuthenticatorFromUsers sorts users into the BasicAuthenticator,
+// BearerTokenAuthenticator, and MTLSAuthenticator they each configure (by
+// which of Password/Token/CertCN is set) and returns a ChainAuthenticator
+// trying all three. Returns nil if users is empty, so callers can pass the
+// result straight to SetAuthenticator without a length check.
+func NewAuthenticatorFromUsers(users []AdminUser) Authenticator {
+	basic := map[string]basicCredential{}
+	bearer := map[string]Principal{}
+	mtls := map[string]Principal{}
+
+	for _, u := range users {
+		switch {
+		case u.Password != "":
+			basic[u.Name] = basicCredential{password: u.Password, role: u.Role}
+		case u.Token != "":
+			bearer[u.Token] = Principal{Name: u.Name, Role: u.Role}
+		case u.CertCN != "":
+			mtls[u.CertCN] = Principal{Name: u.Name, Role: u.Role}
+		}
+	}
+
+	var chain ChainAuthenticator
+	if len(basic) > 0 {
+		chain = append(chain, NewBasicAuthenticator(basic))
+	}
+	if len(bearer) > 0 {
+		chain = append(chain, NewBearerTokenAuthenticator(bearer))
+	}
+	if len(mtls) > 0 {
+		chain = append(chain, NewMTLSAuthenticator(mtls))
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/dht"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeNode is a minimal dht.DHTNode stub for tests that only touch Self();
+// every other method panics via the nil embedded interface if called,
+// which is fine since requireRole/writeJSONError never reach them.
+type fakeNode struct {
+	dht.DHTNode
+	self *domain.Node
+}
+
+func (f *fakeNode) Self() *domain.Node { return f.self }
+
+func newAuthTestServer(auth Authenticator) *HTTPCacheServer {
+	return &HTTPCacheServer{
+		node:          &fakeNode{self: &domain.Node{ID: domain.ID{0x01}, Addr: "127.0.0.1:9000"}},
+		authenticator: auth,
+	}
+}
+
+func TestBasicAuthenticatorAuthenticate(t *testing.T) {
+	a := NewBasicAuthenticator(map[string]basicCredential{
+		"alice": {password: "correct-horse", role: RoleAdmin},
+	})
+
+	tests := []struct {
+		name     string
+		user     string
+		pass     string
+		setAuth  bool
+		wantErr  error
+		wantRole Role
+	}{
+		{name: "valid credentials", user: "alice", pass: "correct-horse", setAuth: true, wantRole: RoleAdmin},
+		{name: "wrong password", user: "alice", pass: "wrong", setAuth: true, wantErr: ErrInvalidCredentials},
+		{name: "unknown user", user: "bob", pass: "whatever", setAuth: true, wantErr: ErrInvalidCredentials},
+		{name: "no credentials", setAuth: false, wantErr: ErrNoCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/debug", nil)
+			if tt.setAuth {
+				r.SetBasicAuth(tt.user, tt.pass)
+			}
+
+			principal, err := a.Authenticate(r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Authenticate() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() unexpected err: %v", err)
+			}
+			if principal.Role != tt.wantRole {
+				t.Errorf("principal.Role = %q, want %q", principal.Role, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestChainAuthenticatorFallsThroughToInvalid(t *testing.T) {
+	basic := NewBasicAuthenticator(map[string]basicCredential{
+		"alice": {password: "correct-horse", role: RoleAdmin},
+	})
+	bearer := NewBearerTokenAuthenticator(map[string]Principal{
+		"good-token": {Name: "svc", Role: RoleRead},
+	})
+	chain := ChainAuthenticator{basic, bearer}
+
+	// A bad basic-auth password should surface ErrInvalidCredentials, not
+	// fall through to ErrNoCredentials just because the bearer link in the
+	// chain also saw no token.
+	r := httptest.NewRequest(http.MethodGet, "/debug", nil)
+	r.SetBasicAuth("alice", "wrong")
+	if _, err := chain.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() err = %v, want ErrInvalidCredentials", err)
+	}
+
+	// No credentials presented to either link: ErrNoCredentials.
+	r = httptest.NewRequest(http.MethodGet, "/debug", nil)
+	if _, err := chain.Authenticate(r); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Authenticate() err = %v, want ErrNoCredentials", err)
+	}
+
+	// A valid bearer token succeeds even though basic auth saw nothing.
+	r = httptest.NewRequest(http.MethodGet, "/debug", nil)
+	r.Header.Set("Authorization", "Bearer good-token")
+	principal, err := chain.Authenticate(r)
+	if err != nil {
+		t.Fatalf("Authenticate() unexpected err: %v", err)
+	}
+	if principal.Name != "svc" {
+		t.Errorf("principal.Name = %q, want %q", principal.Name, "svc")
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	handlerCalled := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		w.WriteHeader(http.StatusOK)
+	}
+
+	auth := NewBasicAuthenticator(map[string]basicCredential{
+		"reader": {password: "pw", role: RoleRead},
+		"admin":  {password: "pw", role: RoleAdmin},
+	})
+
+	tests := []struct {
+		name       string
+		server     *HTTPCacheServer
+		setAuth    func(r *http.Request)
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "no authenticator configured runs handler unconditionally",
+			server:     newAuthTestServer(nil),
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "no credentials is 401",
+			server:     newAuthTestServer(auth),
+			wantStatus: http.StatusUnauthorized,
+			wantCalled: false,
+		},
+		{
+			name:       "read role denied an admin route is 403",
+			server:     newAuthTestServer(auth),
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("reader", "pw") },
+			wantStatus: http.StatusForbidden,
+			wantCalled: false,
+		},
+		{
+			name:       "admin role satisfies an admin route",
+			server:     newAuthTestServer(auth),
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("admin", "pw") },
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerCalled = false
+			r := httptest.NewRequest(http.MethodGet, "/cluster/remove", nil)
+			if tt.setAuth != nil {
+				tt.setAuth(r)
+			}
+			w := httptest.NewRecorder()
+
+			tt.server.requireRole(RoleAdmin, handler)(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if handlerCalled != tt.wantCalled {
+				t.Errorf("handlerCalled = %v, want %v", handlerCalled, tt.wantCalled)
+			}
+		})
+	}
+}
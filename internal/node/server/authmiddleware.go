@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// routeACL declares the minimum Role required to call a handler, for the
+// routes called out as dangerous on a network wider than localhost: the
+// membership-mutating /cluster/add and /cluster/remove, and the
+// topology-disclosing /debug and /debug/watch (the latter streams the
+// same routing-table info as /debug, just incrementally). Routes not
+// wrapped in requireRole (see Start) are unaffected by auth
+// configuration, preserving today's behavior for the cache/health/metrics
+// endpoints.
+var routeACL = map[string]Role{
+	"/cluster/add":    RoleAdmin,
+	"/cluster/remove": RoleAdmin,
+	"/debug":          RoleRead,
+	"/debug/watch":    RoleRead,
+}
+
+type principalContextKey struct{}
+
+// principalFromContext returns the Principal requireRole resolved for this
+// request, if any. Returns nil when the server has no Authenticator
+// configured, since every request then runs unauthenticated.
+func principalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return p
+}
+
+// requireRole wraps handler so it only runs once the caller resolves to a
+// Principal whose Role satisfies min. If s.authenticator is nil, the
+// server has no auth configured at all and handler runs unconditionally,
+// matching the server's pre-auth behavior. Otherwise a caller with no
+// credentials gets 401, and one with credentials that don't satisfy min
+// gets 403 — both as a structured httpError (see jsonerror.go).
+func (s *HTTPCacheServer) requireRole(min Role, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authenticator == nil {
+			handler(w, r)
+			return
+		}
+		principal, err := s.authenticator.Authenticate(r)
+		if err != nil {
+			s.writeJSONError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required", err)
+			return
+		}
+		if !principal.Role.satisfies(min) {
+			s.writeJSONError(w, r, http.StatusForbidden, ErrCodeForbidden,
+				fmt.Sprintf("role %q does not satisfy the %q role required by this route", principal.Role, min), nil)
+			return
+		}
+		handler(w, r.WithContext(context.WithValue(r.Context(), principalContextKey{}, principal)))
+	}
+}
+
+// handleWhoami echoes the caller's resolved Principal, for debugging an
+// Authenticator configuration without having to inspect server logs.
+// Always returns 200: an unauthenticated caller (or a server with no
+// Authenticator configured) simply gets authenticated: false.
+func (s *HTTPCacheServer) handleWhoami(w http.ResponseWriter, r *http.Request) {
+	if s.authenticator == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"authenticated": false})
+		return
+	}
+	principal, err := s.authenticator.Authenticate(r)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"authenticated": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"authenticated": true,
+		"name":          principal.Name,
+		"role":          principal.Role,
+	})
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/cache"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// handleCacheEvents streams CacheEvents — invalidated, evicted, and
+// promoted-to-hotspot — as they happen, so edge caches, browsers, and
+// sibling nodes can react without polling GET /cache or /metrics.
+//
+// Query parameters:
+//
+//	filter=<glob>  only stream events whose URL matches (path.Match
+//	               syntax; omitted or "*" streams everything)
+//
+// The transport is chosen by the request, same as /debug/watch: a
+// WebSocket upgrade (Upgrade: websocket) gets one text frame per event; a
+// plain request gets chunked SSE when "Accept: text/event-stream" is
+// sent, or application/x-ndjson otherwise (so `curl -N /cache/events`
+// works without any special headers). Every event is JSON-encoded on its
+// own frame/line, bounded by EventBus.MaxMessageBytes — if a marshaled
+// event would exceed it, the event is dropped rather than sent truncated,
+// since a partial JSON frame is worse than a missing one.
+func (s *HTTPCacheServer) handleCacheEvents(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get("filter")
+	sub := s.cacheEvents.Subscribe(filter)
+	defer s.cacheEvents.Unsubscribe(sub)
+
+	if isWebSocketUpgrade(r.Header.Get("Upgrade"), r.Header.Get("Connection"),
+		r.Header.Get("Sec-WebSocket-Key"), r.Header.Get("Sec-WebSocket-Version")) {
+		s.serveCacheEventsWebSocket(w, r, sub)
+		return
+	}
+	s.serveCacheEventsSSE(w, r, sub)
+}
+
+// serveCacheEventsWebSocket hijacks the connection to speak the minimal
+// RFC 6455 subset handleCacheEvents needs: a handshake plus one text frame
+// per event, with a background reader that notices when the client closes
+// or sends anything at all (see discardWSFrames).
+func (s *HTTPCacheServer) serveCacheEventsWebSocket(w http.ResponseWriter, r *http.Request, sub *cache.Subscription) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "websocket upgrade not supported by this response writer", nil)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to hijack connection for websocket upgrade", err)
+		return
+	}
+	defer conn.Close()
+
+	if err := writeWSHandshake(rw, r.Header.Get("Sec-WebSocket-Key")); err != nil {
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	clientGone := make(chan struct{})
+	go discardWSFrames(rw.Reader, clientGone)
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-clientGone:
+			return
+		default:
+		}
+
+		e, ok := sub.Next(ctx)
+		if !ok {
+			return
+		}
+
+		payload, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		if len(payload) > s.cacheEvents.MaxMessageBytes() {
+			s.lgr.Warn("handleCacheEvents: dropping event larger than MaxMessageBytes",
+				logger.F("url", e.URL), logger.F("size", len(payload)))
+			continue
+		}
+		if err := writeWSText(rw, payload); err != nil {
+			return
+		}
+		if err := rw.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// serveCacheEventsSSE streams sub over the response writer directly, as
+// SSE or application/x-ndjson depending on Accept, matching
+// handleDebugWatch's existing convention.
+func (s *HTTPCacheServer) serveCacheEventsSSE(w http.ResponseWriter, r *http.Request, sub *cache.Subscription) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "streaming not supported by this response writer", nil)
+		return
+	}
+
+	sse := false
+	for _, accept := range r.Header.Values("Accept") {
+		if accept == "text/event-stream" {
+			sse = true
+			break
+		}
+	}
+
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	for {
+		e, ok := sub.Next(ctx)
+		if !ok {
+			return
+		}
+
+		payload, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		if len(payload) > s.cacheEvents.MaxMessageBytes() {
+			s.lgr.Warn("handleCacheEvents: dropping event larger than MaxMessageBytes",
+				logger.F("url", e.URL), logger.F("size", len(payload)))
+			continue
+		}
+
+		if sse {
+			_, err = fmt.Fprintf(w, "data: %s\n\n", payload)
+		} else {
+			_, err = fmt.Fprintf(w, "%s\n", payload)
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"KoordeDHT/internal/node/cache"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newEventsTestServer() *HTTPCacheServer {
+	return &HTTPCacheServer{
+		node:        &fakeNode{self: nil},
+		cacheEvents: cache.NewEventBus(),
+	}
+}
+
+// runHandleCacheEvents runs handleCacheEvents in a goroutine against a
+// cancelable request, waits for publish to be observed (via the returned
+// channel closing once subscribed events have had time to settle), then
+// cancels and waits for the handler to return before the caller reads the
+// recorder's body.
+func runHandleCacheEvents(t *testing.T, s *HTTPCacheServer, accept string) (*httptest.ResponseRecorder, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/cache/events", nil).WithContext(ctx)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.handleCacheEvents(w, r)
+	}()
+
+	// Give handleCacheEvents time to Subscribe before the caller publishes.
+	time.Sleep(20 * time.Millisecond)
+
+	return w, func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func TestHandleCacheEventsSSE(t *testing.T) {
+	s := newEventsTestServer()
+	w, stop := runHandleCacheEvents(t, s, "text/event-stream")
+
+	s.cacheEvents.Publish(cache.CacheEvent{Type: cache.EventInvalidated, URL: "http://example.com/a", Reason: "deleted"})
+	time.Sleep(20 * time.Millisecond)
+	stop()
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "data: ") || !strings.Contains(body, `"url":"http://example.com/a"`) {
+		t.Errorf("body = %q, want an SSE data frame for the published event", body)
+	}
+}
+
+func TestHandleCacheEventsNDJSON(t *testing.T) {
+	s := newEventsTestServer()
+	w, stop := runHandleCacheEvents(t, s, "")
+
+	s.cacheEvents.Publish(cache.CacheEvent{Type: cache.EventEvicted, URL: "http://example.com/b", Reason: "capacity"})
+	time.Sleep(20 * time.Millisecond)
+	stop()
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+	body := strings.TrimSpace(w.Body.String())
+	if strings.Contains(body, "data: ") {
+		t.Errorf("body = %q, ndjson mode should not use SSE framing", body)
+	}
+	if !strings.Contains(body, `"url":"http://example.com/b"`) {
+		t.Errorf("body = %q, want the published event", body)
+	}
+}
+
+func TestHandleCacheEventsFilterExcludesNonMatchingURLs(t *testing.T) {
+	s := newEventsTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/cache/events?filter=hot-*", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.handleCacheEvents(w, r)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	s.cacheEvents.Publish(cache.CacheEvent{Type: cache.EventEvicted, URL: "/cold/x", Reason: "expired"})
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	if body := w.Body.String(); strings.Contains(body, "/cold/x") {
+		t.Errorf("body = %q, filtered-out event leaked through", body)
+	}
+}
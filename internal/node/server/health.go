@@ -0,0 +1,257 @@
+package server
+
+import (
+	"KoordeDHT/internal/logger"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PeerHealthStatus is a snapshot of one peer address's health, exposed via
+// /metrics and /health/peers.
+type PeerHealthStatus struct {
+	Healthy              bool      `json:"healthy"`
+	ConsecutiveFailures  int       `json:"consecutive_failures"`
+	ConsecutiveSuccesses int       `json:"consecutive_successes"`
+	LastCheckLatencyMs   float64   `json:"last_check_latency_ms"`
+	LastCheckTime        time.Time `json:"last_check_time"`
+	QuarantinedUntil     time.Time `json:"quarantined_until,omitempty"`
+}
+
+type peerHealthEntry struct {
+	mu sync.Mutex
+	PeerHealthStatus
+}
+
+// PeerHealthConfig configures PeerHealth's active checker and passive
+// quarantine behavior.
+type PeerHealthConfig struct {
+	CheckInterval time.Duration
+	CheckTimeout  time.Duration
+
+	// UnhealthyThreshold/HealthyThreshold are consecutive active-check
+	// failures/successes required to flip a peer's status.
+	UnhealthyThreshold int
+	HealthyThreshold   int
+
+	// PassiveFailureThreshold/PassiveWindow govern the passive path:
+	// PassiveFailureThreshold proxy failures within PassiveWindow
+	// quarantine the peer for QuarantineCooldown.
+	PassiveFailureThreshold int
+	PassiveWindow           time.Duration
+	QuarantineCooldown      time.Duration
+}
+
+// DefaultPeerHealthConfig returns reasonable defaults: 2 consecutive
+// active-check failures to go unhealthy, 1 success to recover, 3 passive
+// proxy failures within 30s to quarantine for 30s.
+func DefaultPeerHealthConfig() PeerHealthConfig {
+	return PeerHealthConfig{
+		CheckInterval:           10 * time.Second,
+		CheckTimeout:            2 * time.Second,
+		UnhealthyThreshold:      2,
+		HealthyThreshold:        1,
+		PassiveFailureThreshold: 3,
+		PassiveWindow:           30 * time.Second,
+		QuarantineCooldown:      30 * time.Second,
+	}
+}
+
+// PeerHealth tracks the health of peer addresses (the same gRPC "host:port"
+// addresses SuccessorList/DeBruijnList/FingerList/Predecessor return),
+// combining active /health polling with passive proxy-failure tracking —
+// the same active+passive model Caddy's reverse proxy uses to avoid
+// routing to a peer that's already dead.
+type PeerHealth struct {
+	cfg            PeerHealthConfig
+	lgr            logger.Logger
+	client         *http.Client
+	httpPortOffset int
+
+	mu    sync.RWMutex
+	peers map[string]*peerHealthEntry
+}
+
+// NewPeerHealth creates a PeerHealth. httpPortOffset is added to a peer's
+// gRPC port to reach its HTTP /health endpoint, mirroring how proxyToNode
+// derives a peer's HTTP address.
+func NewPeerHealth(cfg PeerHealthConfig, lgr logger.Logger, httpPortOffset int) *PeerHealth {
+	return &PeerHealth{
+		cfg:            cfg,
+		lgr:            lgr,
+		client:         &http.Client{Timeout: cfg.CheckTimeout},
+		httpPortOffset: httpPortOffset,
+		peers:          make(map[string]*peerHealthEntry),
+	}
+}
+
+func (ph *PeerHealth) entry(addr string) *peerHealthEntry {
+	ph.mu.RLock()
+	e, ok := ph.peers[addr]
+	ph.mu.RUnlock()
+	if ok {
+		return e
+	}
+
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+	if e, ok = ph.peers[addr]; ok {
+		return e
+	}
+	e = &peerHealthEntry{PeerHealthStatus: PeerHealthStatus{Healthy: true}}
+	ph.peers[addr] = e
+	return e
+}
+
+// IsHealthy reports whether addr is currently eligible for forwarding.
+// Addresses never seen before are assumed healthy, so a brand-new peer
+// isn't excluded before its first check. A quarantined peer becomes
+// eligible again once QuarantinedUntil has passed, even if no active check
+// has succeeded yet.
+func (ph *PeerHealth) IsHealthy(addr string) bool {
+	e := ph.entry(addr)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.Healthy && !e.QuarantinedUntil.IsZero() && time.Now().After(e.QuarantinedUntil) {
+		e.Healthy = true
+		e.QuarantinedUntil = time.Time{}
+		e.ConsecutiveFailures = 0
+	}
+	return e.Healthy
+}
+
+// RecordProxyFailure is the passive path: proxyToNode calls this when a
+// forward to addr fails outright (not merely a non-2xx response). After
+// PassiveFailureThreshold failures within PassiveWindow, addr is quarantined
+// for QuarantineCooldown.
+func (ph *PeerHealth) RecordProxyFailure(addr string) {
+	e := ph.entry(addr)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	if !e.LastCheckTime.IsZero() && now.Sub(e.LastCheckTime) > ph.cfg.PassiveWindow {
+		e.ConsecutiveFailures = 0
+	}
+	e.ConsecutiveFailures++
+	e.ConsecutiveSuccesses = 0
+	e.LastCheckTime = now
+
+	if e.ConsecutiveFailures >= ph.cfg.PassiveFailureThreshold && e.Healthy {
+		ph.lgr.Warn("PeerHealth: quarantining peer after passive proxy failures",
+			logger.F("addr", addr), logger.F("failures", e.ConsecutiveFailures))
+		e.Healthy = false
+		e.QuarantinedUntil = now.Add(ph.cfg.QuarantineCooldown)
+	}
+}
+
+// RecordProxySuccess is the passive path's counterpart: proxyToNode calls
+// this after a successful forward, resetting addr's passive failure count.
+func (ph *PeerHealth) RecordProxySuccess(addr string) {
+	e := ph.entry(addr)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ConsecutiveFailures = 0
+}
+
+// Status returns a snapshot of every peer PeerHealth currently tracks, for
+// /metrics and /health/peers.
+func (ph *PeerHealth) Status() map[string]PeerHealthStatus {
+	ph.mu.RLock()
+	defer ph.mu.RUnlock()
+	out := make(map[string]PeerHealthStatus, len(ph.peers))
+	for addr, e := range ph.peers {
+		e.mu.Lock()
+		out[addr] = e.PeerHealthStatus
+		e.mu.Unlock()
+	}
+	return out
+}
+
+// Start launches the active checker goroutine, which periodically GETs
+// /health on every address addrsFn returns (typically knownPeerAddrs,
+// i.e. SuccessorList + DeBruijnList + FingerList + Predecessor), until ctx
+// is done.
+func (ph *PeerHealth) Start(ctx context.Context, addrsFn func() []string) {
+	go func() {
+		ticker := time.NewTicker(ph.cfg.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, addr := range addrsFn() {
+					ph.checkOne(addr)
+				}
+			}
+		}
+	}()
+}
+
+// checkOne performs a single active /health check against addr's HTTP
+// endpoint and folds the result into addr's consecutive-failure/success
+// counters, flipping Healthy once UnhealthyThreshold/HealthyThreshold is
+// crossed.
+func (ph *PeerHealth) checkOne(addr string) {
+	url, err := ph.healthURL(addr)
+	if err != nil {
+		ph.lgr.Debug("PeerHealth: cannot build health check URL", logger.F("addr", addr), logger.F("err", err))
+		return
+	}
+
+	start := time.Now()
+	resp, err := ph.client.Get(url)
+	latencyMs := float64(time.Since(start).Microseconds()) / 1000
+
+	e := ph.entry(addr)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.LastCheckTime = time.Now()
+	e.LastCheckLatencyMs = latencyMs
+
+	ok := err == nil
+	if ok {
+		defer resp.Body.Close()
+		ok = resp.StatusCode < 500
+	}
+
+	if ok {
+		e.ConsecutiveSuccesses++
+		e.ConsecutiveFailures = 0
+		if !e.Healthy && e.ConsecutiveSuccesses >= ph.cfg.HealthyThreshold {
+			ph.lgr.Info("PeerHealth: peer recovered", logger.F("addr", addr))
+			e.Healthy = true
+			e.QuarantinedUntil = time.Time{}
+		}
+		return
+	}
+
+	e.ConsecutiveFailures++
+	e.ConsecutiveSuccesses = 0
+	if e.Healthy && e.ConsecutiveFailures >= ph.cfg.UnhealthyThreshold {
+		ph.lgr.Warn("PeerHealth: peer failed active health check, marking unhealthy",
+			logger.F("addr", addr), logger.F("failures", e.ConsecutiveFailures), logger.F("err", err))
+		e.Healthy = false
+		e.QuarantinedUntil = time.Now().Add(ph.cfg.QuarantineCooldown)
+	}
+}
+
+// healthURL derives a peer's HTTP /health URL from its gRPC "host:port"
+// address, the same translation proxyToNode applies to reach /cache.
+func (ph *PeerHealth) healthURL(grpcAddr string) (string, error) {
+	host, portStr, err := net.SplitHostPort(grpcAddr)
+	if err != nil {
+		return "", fmt.Errorf("peer health: cannot parse address %q: %w", grpcAddr, err)
+	}
+	grpcPort, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", fmt.Errorf("peer health: cannot parse gRPC port %q: %w", portStr, err)
+	}
+	return fmt.Sprintf("http://%s:%d/health", host, grpcPort+ph.httpPortOffset), nil
+}
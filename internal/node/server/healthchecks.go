@@ -0,0 +1,150 @@
+package server
+
+import (
+	"KoordeDHT/internal/healthcheck"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"syscall"
+)
+
+const defaultMinFreeDiskBytes = 100 * 1024 * 1024 // 100 MiB
+
+// buildHealthRegistry wires up this server's HealthCheck set: successor
+// presence and de Bruijn degree gate readiness ("don't route here yet");
+// storage reachability, RPC transport, a completed stabilizer round, and
+// disk space gate liveness ("restart me"). This replaces the old single
+// ad hoc handleHealth field-checking with a healthcheck.Registry, the same
+// split etcd's health subsystem uses.
+func (s *HTTPCacheServer) buildHealthRegistry() *healthcheck.Registry {
+	reg := healthcheck.NewRegistry()
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "successor",
+		CheckClass: healthcheck.Readiness,
+		Fn: func(ctx context.Context) error {
+			if s.node.Self() == nil {
+				return fmt.Errorf("node not initialized")
+			}
+			if len(s.node.SuccessorList()) == 0 && s.node.EstimateNetworkSize() > 1 {
+				return fmt.Errorf("no successors known")
+			}
+			return nil
+		},
+	})
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "debruijn",
+		CheckClass: healthcheck.Readiness,
+		Fn: func(ctx context.Context) error {
+			if s.node.RoutingMetrics().Protocol != "koorde" {
+				return nil
+			}
+			// Require at least 1 de Bruijn neighbor; full degree may
+			// exceed the cluster's current size.
+			if len(s.node.DeBruijnList()) == 0 && s.node.EstimateNetworkSize() > 1 {
+				return fmt.Errorf("no de Bruijn neighbors known")
+			}
+			return nil
+		},
+	})
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "storage",
+		CheckClass: healthcheck.Liveness,
+		Fn: func(ctx context.Context) error {
+			// GetAllResourceStored has no separate reachability signal
+			// to surface today — this check exists so a storage backend
+			// that gains real I/O (disk, remote) can fail it later
+			// without any registry rewiring.
+			s.node.GetAllResourceStored()
+			return nil
+		},
+	})
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "rpc_transport",
+		CheckClass: healthcheck.Liveness,
+		Fn: func(ctx context.Context) error {
+			self := s.node.Self()
+			if self == nil || self.Addr == "" {
+				return fmt.Errorf("node has no advertised RPC address")
+			}
+			return nil
+		},
+	})
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "stabilizer_heartbeat",
+		CheckClass: healthcheck.Liveness,
+		Fn: func(ctx context.Context) error {
+			if s.node.EstimateNetworkSize() > 1 && s.node.RoutingMetrics().AvgStabilizationRoundMs == 0 {
+				return fmt.Errorf("stabilizer has not completed a round yet")
+			}
+			return nil
+		},
+	})
+
+	reg.Register(healthcheck.FuncCheck{
+		CheckName:  "disk_space",
+		CheckClass: healthcheck.Liveness,
+		Fn: func(ctx context.Context) error {
+			return checkDiskSpace(s.diskSpacePath, s.minFreeDiskBytes)
+		},
+	})
+
+	return reg
+}
+
+// checkDiskSpace fails if the filesystem holding path has fewer than
+// minFreeBytes available.
+func checkDiskSpace(path string, minFreeBytes uint64) error {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return fmt.Errorf("disk space check: %w", err)
+	}
+
+	free := uint64(stat.Bavail) * uint64(stat.Bsize)
+	if free < minFreeBytes {
+		return fmt.Errorf("disk space check: only %d bytes free at %q (want >= %d)", free, path, minFreeBytes)
+	}
+	return nil
+}
+
+// excludeSet turns repeated "?exclude=name" query params into a lookup
+// set, e.g. "?exclude=debruijn&exclude=storage" to skip those two checks.
+func excludeSet(r *http.Request) map[string]bool {
+	exclude := make(map[string]bool)
+	for _, name := range r.URL.Query()["exclude"] {
+		exclude[name] = true
+	}
+	return exclude
+}
+
+func writeHealthReport(w http.ResponseWriter, report healthcheck.Report) {
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleHealth reports every registered check (both liveness and
+// readiness) in one breakdown — the original single /health endpoint,
+// now backed by healthcheck.Registry instead of ad hoc field checks.
+func (s *HTTPCacheServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.healthRegistry.RunAll(r.Context(), excludeSet(r)))
+}
+
+// handleLivez reports only Liveness checks. Failure means Kubernetes (or
+// any orchestrator) should restart this process.
+func (s *HTTPCacheServer) handleLivez(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.healthRegistry.Run(r.Context(), healthcheck.Liveness, excludeSet(r)))
+}
+
+// handleReadyz reports only Readiness checks. Failure means this node
+// shouldn't receive traffic yet, without implying it needs restarting.
+func (s *HTTPCacheServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.healthRegistry.Run(r.Context(), healthcheck.Readiness, excludeSet(r)))
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"KoordeDHT/internal/logger"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// defaultReplicationFactor is how many peers handleHotURL pushes a hot
+// entry to when no SetReplicationFactor override is configured.
+const defaultReplicationFactor = 2
+
+// defaultReplicaPushTimeout bounds how long handleHotURL waits for a
+// single peer to accept a pushed entry, so one unresponsive replica can't
+// hold up the others.
+const defaultReplicaPushTimeout = 2 * time.Second
+
+// replicaPushRequest is the POST /cache/replica body: a hot entry pushed
+// proactively by the node responsible for url, so the receiving peer can
+// serve it locally without a round trip to the responsible node or the
+// origin.
+type replicaPushRequest struct {
+	URL         string `json:"url"`
+	Content     []byte `json:"content"`
+	ContentType string `json:"content_type"`
+	StatusCode  int    `json:"status_code"`
+	TTLSeconds  int64  `json:"ttl_seconds"`
+}
+
+// SetReplicationFactor overrides how many peers a hot URL is replicated
+// to (see handleHotURL). Defaults to defaultReplicationFactor.
+func (s *HTTPCacheServer) SetReplicationFactor(n int) {
+	s.replicationFactor = n
+}
+
+// hotReplicasFor returns a snapshot of url's currently known replica
+// addresses. /debug and /metrics report every URL's via allHotReplicas.
+func (s *HTTPCacheServer) hotReplicasFor(url string) []string {
+	s.hotReplicasMu.RLock()
+	defer s.hotReplicasMu.RUnlock()
+	addrs := s.hotReplicas[url]
+	out := make([]string, len(addrs))
+	copy(out, addrs)
+	return out
+}
+
+// allHotReplicas returns a snapshot of every URL's currently known replica
+// addresses, for /debug to report.
+func (s *HTTPCacheServer) allHotReplicas() map[string][]string {
+	s.hotReplicasMu.RLock()
+	defer s.hotReplicasMu.RUnlock()
+	out := make(map[string][]string, len(s.hotReplicas))
+	for url, addrs := range s.hotReplicas {
+		cp := make([]string, len(addrs))
+		copy(cp, addrs)
+		out[url] = cp
+	}
+	return out
+}
+
+// handleHotURL is registered as the HotspotDetector's OnHot callback. It
+// peeks url's cached entry and proactively pushes it to up to
+// replicationFactor peers chosen from candidateNodes (the same
+// successor/de-Bruijn/predecessor pool the client-facing handler already
+// selects fallback targets from), so subsequent requests for url can be
+// load-balanced across replicas by NodeSelector instead of all landing on
+// this node. Entries this node hasn't actually cached yet (e.g. a hot
+// lookup proxied elsewhere) have nothing to push and are skipped.
+func (s *HTTPCacheServer) handleHotURL(url string, avg float64) {
+	entry, ok := s.cache.Peek(url)
+	if !ok {
+		return
+	}
+
+	factor := s.replicationFactor
+	if factor <= 0 {
+		factor = defaultReplicationFactor
+	}
+
+	targets := s.candidateNodes()
+	if len(targets) > factor {
+		targets = targets[:factor]
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(replicaPushRequest{
+		URL:         entry.URL,
+		Content:     entry.Content,
+		ContentType: entry.ContentType,
+		StatusCode:  entry.StatusCode,
+		TTLSeconds:  int64(time.Until(entry.Expiration).Seconds()),
+	})
+	if err != nil {
+		s.lgr.Warn("handleHotURL: failed to marshal replica push body",
+			logger.F("url", url), logger.F("err", err))
+		return
+	}
+
+	replicas := make([]string, 0, len(targets))
+	for _, nodeAddr := range targets {
+		target, err := s.httpURLFor(nodeAddr)
+		if err != nil {
+			continue
+		}
+		target.Path = "/cache/replica"
+
+		req, err := http.NewRequest(http.MethodPost, target.String(), bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.replicaPushClient().Do(req)
+		if err != nil {
+			s.lgr.Debug("handleHotURL: replica push failed",
+				logger.F("url", url), logger.F("target", nodeAddr), logger.F("err", err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			replicas = append(replicas, nodeAddr)
+		}
+	}
+
+	s.lgr.Info("Hot URL replicated to peers",
+		logger.F("url", url), logger.F("avg_rate", avg), logger.F("replicas", replicas))
+
+	s.hotReplicasMu.Lock()
+	if len(replicas) > 0 {
+		s.hotReplicas[url] = replicas
+	} else {
+		delete(s.hotReplicas, url)
+	}
+	s.hotReplicasMu.Unlock()
+}
+
+// replicaPushClient lazily builds the *http.Client handleHotURL pushes
+// replicas with. Built on first use (rather than in NewHTTPCacheServer)
+// so a zero-value HTTPCacheServer in tests doesn't need one.
+func (s *HTTPCacheServer) replicaPushClient() *http.Client {
+	s.replicaClientOnce.Do(func() {
+		s.replicaClient = &http.Client{Timeout: defaultReplicaPushTimeout}
+	})
+	return s.replicaClient
+}
+
+// handleCacheReplica accepts a hot entry proactively pushed by the node
+// responsible for it (see handleHotURL) and stores it locally, so this
+// node can serve it on subsequent requests without proxying back. Left
+// unauthenticated like /cache, since it discloses no topology and only
+// stores content this node's own peers chose to push.
+func (s *HTTPCacheServer) handleCacheReplica(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeJSONError(w, r, http.StatusMethodNotAllowed, ErrCodeInvalidRequest, "method not allowed, use POST", nil)
+		return
+	}
+
+	var req replicaPushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid request body", err)
+		return
+	}
+	if req.URL == "" {
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing 'url' field", nil)
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = nearCacheTTL
+	}
+	if err := s.cache.Put(req.URL, req.Content, req.ContentType, ttl, req.StatusCode); err != nil {
+		s.lgr.Warn("handleCacheReplica: failed to store pushed replica",
+			logger.F("url", req.URL), logger.F("err", err))
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to store replica", err)
+		return
+	}
+
+	s.lgr.Debug("Stored pushed replica", logger.F("url", req.URL))
+	writeJSON(w, http.StatusOK, map[string]string{"status": "stored"})
+}
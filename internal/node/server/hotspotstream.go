@@ -0,0 +1,82 @@
+package server
+
+import (
+	"KoordeDHT/internal/node/cache"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// hotspotStreamHeartbeatInterval is how often handleHotspotStream writes
+// an SSE comment to keep intermediating proxies from closing an idle
+// connection, matching handleDebugWatch's convention.
+const hotspotStreamHeartbeatInterval = 10 * time.Second
+
+// hotspotStreamEvent is the JSON payload handleHotspotStream writes for
+// each cache.HotspotEvent, adding the fields only the server (not
+// HotspotDetector) knows: which node observed the transition.
+type hotspotStreamEvent struct {
+	Type          cache.HotspotEventKind `json:"type"`
+	URL           string                 `json:"url"`
+	Average       float64                `json:"average"`
+	TotalRequests float64                `json:"total_requests"`
+	NodeID        string                 `json:"node_id"`
+	Timestamp     time.Time              `json:"ts"`
+}
+
+// handleHotspotStream streams HotspotDetector's hot/cool transitions as
+// Server-Sent Events, so operators get a live view of traffic shifts
+// across the cluster without polling GET /metrics. Each event is one SSE
+// "data:" line of JSON: {type: "hot"|"cool", url, average,
+// total_requests, node_id, ts}. A heartbeat comment is written every
+// hotspotStreamHeartbeatInterval to keep the connection alive through
+// intermediating proxies.
+func (s *HTTPCacheServer) handleHotspotStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "streaming not supported by this response writer", nil)
+		return
+	}
+
+	ch := s.hotspotEvents.Subscribe()
+	defer s.hotspotEvents.Unsubscribe(ch)
+
+	nodeID := s.node.Self().ID.ToHexString(true)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	heartbeat := time.NewTicker(hotspotStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-ch:
+			payload, err := json.Marshal(hotspotStreamEvent{
+				Type:          e.Type,
+				URL:           e.URL,
+				Average:       e.Average,
+				TotalRequests: e.TotalRequests,
+				NodeID:        nodeID,
+				Timestamp:     e.Timestamp,
+			})
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
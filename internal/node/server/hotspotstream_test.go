@@ -0,0 +1,77 @@
+package server
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/cache"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newHotspotStreamTestServer() *HTTPCacheServer {
+	return &HTTPCacheServer{
+		node:          &fakeNode{self: &domain.Node{ID: domain.ID{0xAB}, Addr: "127.0.0.1:9000"}},
+		hotspotEvents: cache.NewHotspotBroadcaster(),
+	}
+}
+
+func TestHandleHotspotStream(t *testing.T) {
+	s := newHotspotStreamTestServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/hotspots/stream", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.handleHotspotStream(w, r)
+	}()
+
+	// Give handleHotspotStream time to Subscribe before we publish.
+	time.Sleep(20 * time.Millisecond)
+
+	s.hotspotEvents.Publish(cache.HotspotEvent{
+		Type:          cache.HotspotEventHot,
+		URL:           "http://example.com/viral",
+		Average:       42.5,
+		TotalRequests: 100,
+		Timestamp:     time.Now(),
+	})
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	body := w.Body.String()
+	const prefix = "data: "
+	idx := strings.Index(body, prefix)
+	if idx < 0 {
+		t.Fatalf("body = %q, want an SSE data frame for the published event", body)
+	}
+	line := body[idx+len(prefix):]
+	line = line[:strings.Index(line, "\n")]
+
+	var got hotspotStreamEvent
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("unmarshal event frame %q: %v", line, err)
+	}
+	if got.Type != cache.HotspotEventHot {
+		t.Errorf("Type = %q, want %q", got.Type, cache.HotspotEventHot)
+	}
+	if got.URL != "http://example.com/viral" {
+		t.Errorf("URL = %q, want %q", got.URL, "http://example.com/viral")
+	}
+	if got.NodeID != "0xab" {
+		t.Errorf("NodeID = %q, want %q (this node's self ID hex)", got.NodeID, "0xab")
+	}
+}
@@ -2,18 +2,24 @@ package server
 
 import (
 	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/healthcheck"
 	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/metrics"
 	"KoordeDHT/internal/node/cache"
 	"KoordeDHT/internal/node/dht"
+	"KoordeDHT/internal/node/origin"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -21,6 +27,12 @@ const (
 	nearCacheTTL   = 5 * time.Minute
 )
 
+// defaultOriginFetcher is the zero-config OriginFetcher every
+// HTTPCacheServer starts with: no per-URL rules, honoring
+// http.ProxyFromEnvironment. A config with Rules can never fail to
+// compile here since there are none, so the error is safely ignored.
+var defaultOriginFetcher, _ = origin.NewFetcher(origin.Config{})
+
 // HTTPCacheServer provides HTTP endpoints for web caching functionality
 type HTTPCacheServer struct {
 	node                 dht.DHTNode
@@ -30,6 +42,98 @@ type HTTPCacheServer struct {
 	server               *http.Server
 	lgr                  logger.Logger
 	grpcToHTTPPortOffset int
+
+	// proxyFlushInterval is passed to the streaming reverse proxy's
+	// httputil.ReverseProxy.FlushInterval (see proxy.go). -1 flushes after
+	// every write, which SSE/WebSocket-like responses need to avoid
+	// buffering indefinitely.
+	proxyFlushInterval time.Duration
+
+	// proxyTeeCacheMaxBytes caps how large a proxied response body may be
+	// for the reverse proxy to opportunistically cache it locally; larger
+	// bodies are streamed to the client without being cached.
+	proxyTeeCacheMaxBytes int64
+
+	// selector picks which peer to send a request to during hotspot
+	// distribution (see selector.go). Defaults to RandomSelector.
+	selector NodeSelector
+
+	// peerStats tracks in-flight counts and EWMA latency per peer address,
+	// read by selector (e.g. LeastRequestsSelector) and updated around
+	// every proxied hotspot request.
+	peerStats *PeerStats
+
+	// peerHealth tracks which known peers are eligible for forwarding (see
+	// health.go), combining active /health polling with passive
+	// proxy-failure tracking.
+	peerHealth *PeerHealth
+
+	healthCheckCancel context.CancelFunc
+
+	// originFetcher performs fetchFromOrigin's actual egress, owning the
+	// outbound transport(s) (see internal/node/origin) — overridable via
+	// SetOriginFetcher for corporate-proxy/per-URL-class routing.
+	originFetcher *origin.Fetcher
+
+	// healthRegistry backs /health, /livez, and /readyz (see
+	// healthchecks.go) — a composable set of named HealthChecks classified
+	// as Liveness or Readiness, replacing the old single ad hoc handler.
+	healthRegistry *healthcheck.Registry
+
+	// diskSpacePath and minFreeDiskBytes parameterize the "disk_space"
+	// liveness check. Defaults to the working directory and
+	// defaultMinFreeDiskBytes; override with SetDiskSpaceCheck.
+	diskSpacePath    string
+	minFreeDiskBytes uint64
+
+	// promRegistry backs GET /metrics/prometheus (see handlePrometheus):
+	// the DHT routing exporter (internal/metrics.Exporter) plus this
+	// server's own cacheCollector and membershipChangesTotal counter, kept
+	// separate from the JSON /metrics used by the cache-client CLI.
+	promRegistry           *prometheus.Registry
+	membershipChangesTotal *prometheus.CounterVec
+
+	// authenticator resolves the caller of an admin/topology route (see
+	// routeACL, requireRole in authmiddleware.go). nil by default, which
+	// leaves every route unauthenticated — set via SetAuthenticator.
+	authenticator Authenticator
+
+	// cacheEvents fans out WebCache/HotspotDetector invalidation, eviction,
+	// and promotion events to GET /cache/events subscribers (see events.go).
+	cacheEvents *cache.EventBus
+
+	// hotspotEvents fans out HotspotDetector hot/cool transitions to GET
+	// /hotspots/stream subscribers (see hotspotstream.go).
+	hotspotEvents *cache.HotspotBroadcaster
+
+	// replicationFactor is how many peers handleHotURL pushes a hot entry
+	// to (see hotreplicate.go). Defaults to defaultReplicationFactor;
+	// override with SetReplicationFactor.
+	replicationFactor int
+
+	// hotReplicas tracks, per URL, the peer addresses handleHotURL most
+	// recently pushed that URL's entry to — surfaced via /debug and
+	// /metrics so the interactive client's hotspots command can show them.
+	hotReplicasMu sync.RWMutex
+	hotReplicas   map[string][]string
+
+	// replicaClient is the *http.Client handleHotURL pushes replicas with,
+	// lazily built by replicaPushClient.
+	replicaClient     *http.Client
+	replicaClientOnce sync.Once
+
+	// statsd is the optional UDP statsd backend sampled by
+	// statsdSampleLoop (see statsdsample.go). nil by default, set via
+	// SetStatsdEmitter, which leaves statsd entirely disabled.
+	statsd *metrics.StatsdEmitter
+
+	// revalidatingMu guards revalidating, the set of URLs with a
+	// stale-while-revalidate background fetch in flight (see
+	// revalidateInBackground in revalidate.go), so a hot URL's concurrent
+	// stale hits share a single origin fetch instead of each spawning its
+	// own goroutine and request.
+	revalidatingMu sync.Mutex
+	revalidating   map[string]struct{}
 }
 
 // NewHTTPCacheServer creates a new HTTP cache server instance
@@ -55,14 +159,91 @@ func NewHTTPCacheServer(
 		}
 	}
 
-	return &HTTPCacheServer{
-		node:                 node,
-		cache:                webCache,
-		hotspotDetector:      hotspotDetector,
-		port:                 port,
-		lgr:                  lgr,
-		grpcToHTTPPortOffset: offset,
+	s := &HTTPCacheServer{
+		node:                  node,
+		cache:                 webCache,
+		hotspotDetector:       hotspotDetector,
+		port:                  port,
+		lgr:                   lgr,
+		grpcToHTTPPortOffset:  offset,
+		proxyFlushInterval:    defaultProxyFlushInterval,
+		proxyTeeCacheMaxBytes: defaultProxyTeeCacheMaxBytes,
+		selector:              RandomSelector{},
+		peerStats:             NewPeerStats(),
+		peerHealth:            NewPeerHealth(DefaultPeerHealthConfig(), lgr, offset),
+		originFetcher:         defaultOriginFetcher,
+		diskSpacePath:         ".",
+		minFreeDiskBytes:      defaultMinFreeDiskBytes,
+		replicationFactor:     defaultReplicationFactor,
+		hotReplicas:           make(map[string][]string),
+		revalidating:          make(map[string]struct{}),
 	}
+	s.healthRegistry = s.buildHealthRegistry()
+
+	s.cacheEvents = cache.NewEventBus()
+	webCache.SetEventBus(s.cacheEvents)
+	hotspotDetector.SetEventBus(s.cacheEvents)
+	hotspotDetector.OnHot(s.handleHotURL)
+
+	s.hotspotEvents = cache.NewHotspotBroadcaster()
+	hotspotDetector.SetBroadcaster(s.hotspotEvents)
+
+	s.membershipChangesTotal = newMembershipChangesCounter()
+	s.promRegistry = prometheus.NewRegistry()
+	s.promRegistry.MustRegister(metrics.NewExporter(node))
+	s.promRegistry.MustRegister(newCacheCollector(webCache, s.cacheEvents))
+	s.promRegistry.MustRegister(s.membershipChangesTotal)
+
+	return s
+}
+
+// SetOriginFetcher overrides the OriginFetcher used by fetchFromOrigin —
+// e.g. to route egress through a corporate proxy, add per-URL-pattern
+// upstream rules, or point all traffic at a test recorder (see
+// internal/node/origin.Config).
+func (s *HTTPCacheServer) SetOriginFetcher(fetcher *origin.Fetcher) {
+	s.originFetcher = fetcher
+}
+
+// SetPeerHealthConfig overrides the active/passive health-checking
+// thresholds used by PeerHealth. Must be called before Start.
+func (s *HTTPCacheServer) SetPeerHealthConfig(cfg PeerHealthConfig) {
+	s.peerHealth = NewPeerHealth(cfg, s.lgr, s.grpcToHTTPPortOffset)
+}
+
+// SetDiskSpaceCheck overrides the path and minimum free-byte threshold
+// used by the "disk_space" liveness check. Must be called before Start.
+func (s *HTTPCacheServer) SetDiskSpaceCheck(path string, minFreeBytes uint64) {
+	s.diskSpacePath = path
+	s.minFreeDiskBytes = minFreeBytes
+}
+
+// SetNodeSelector overrides the policy used to pick a peer during hotspot
+// distribution. Defaults to RandomSelector, matching the prior inline
+// behavior.
+func (s *HTTPCacheServer) SetNodeSelector(selector NodeSelector) {
+	s.selector = selector
+}
+
+// SetAuthenticator installs the Authenticator used by requireRole to
+// resolve and authorize callers of the routes in routeACL. Passing nil
+// (the default) leaves every route unauthenticated. Must be called before
+// Start.
+func (s *HTTPCacheServer) SetAuthenticator(a Authenticator) {
+	s.authenticator = a
+}
+
+// SetProxyFlushInterval overrides the streaming reverse proxy's flush
+// interval (see httputil.ReverseProxy.FlushInterval). Pass -1 for
+// SSE/WebSocket-like traffic that must not be buffered at all.
+func (s *HTTPCacheServer) SetProxyFlushInterval(d time.Duration) {
+	s.proxyFlushInterval = d
+}
+
+// SetProxyTeeCacheMaxBytes overrides the size threshold under which a
+// proxied response body is opportunistically cached locally.
+func (s *HTTPCacheServer) SetProxyTeeCacheMaxBytes(n int64) {
+	s.proxyTeeCacheMaxBytes = n
 }
 
 // Start launches the HTTP server and blocks until stopped
@@ -75,15 +256,59 @@ func (s *HTTPCacheServer) Start() error {
 	// Metrics endpoint
 	mux.HandleFunc("/metrics", s.handleMetrics)
 
-	// Health check
-	mux.HandleFunc("/health", s.handleHealth)
+	// Prometheus exposition format, for scraping by a real Prometheus
+	// server. Kept at a separate route from the JSON /metrics above, which
+	// the cache-client CLI depends on.
+	mux.Handle("/metrics/prometheus", promhttp.HandlerFor(s.promRegistry, promhttp.HandlerOpts{}))
 
-	// Debug endpoint (routing table info)
-	mux.HandleFunc("/debug", s.handleDebug)
+	// Last statsd sample, for local inspection without a real statsd
+	// collector running (see statsdsample.go).
+	mux.HandleFunc("/metrics/statsd", s.handleStatsdSample)
 
-	// Cluster membership update endpoints (simple hash only)
-	mux.HandleFunc("/cluster/remove", s.handleClusterRemove)
-	mux.HandleFunc("/cluster/add", s.handleClusterAdd)
+	// Health check endpoints, all backed by healthRegistry (see
+	// healthchecks.go): /health reports every check, /livez only Liveness
+	// (restart-worthy), /readyz only Readiness (route-worthy).
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/livez", s.handleLivez)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+
+	// Debug endpoint (routing table info). Topology-disclosing, so it's
+	// gated behind routeACL/requireRole (see authmiddleware.go).
+	mux.HandleFunc("/debug", s.requireRole(routeACL["/debug"], s.handleDebug))
+
+	// Streaming routing-table diff watch (see watch.go). Topology-disclosing
+	// like /debug, so it's gated the same way.
+	mux.HandleFunc("/debug/watch", s.requireRole(routeACL["/debug/watch"], s.handleDebugWatch))
+
+	// Streaming cache invalidation/eviction/hotspot-promotion feed (see
+	// events.go), SSE or WebSocket depending on the request headers.
+	mux.HandleFunc("/cache/events", s.handleCacheEvents)
+
+	// Streaming hot/cool hotspot transition feed (see hotspotstream.go).
+	mux.HandleFunc("/hotspots/stream", s.handleHotspotStream)
+
+	// Inter-node replica push (see hotreplicate.go). Unauthenticated like
+	// /cache: it discloses no topology and only stores content a peer
+	// already decided to push.
+	mux.HandleFunc("/cache/replica", s.handleCacheReplica)
+
+	// Peer health debug endpoint
+	mux.HandleFunc("/health/peers", s.handlePeerHealth)
+
+	// Cluster membership endpoints. /cluster/members is the first-class,
+	// protocol-generic API (see handleClusterMembers); /cluster/add and
+	// /cluster/remove are kept for existing callers. The two mutating
+	// legacy routes are gated behind routeACL/requireRole; handleClusterMembers
+	// gates its POST and DELETE the same way internally, since it
+	// dispatches by method rather than route.
+	mux.HandleFunc("/cluster/remove", s.requireRole(routeACL["/cluster/remove"], s.handleClusterRemove))
+	mux.HandleFunc("/cluster/add", s.requireRole(routeACL["/cluster/add"], s.handleClusterAdd))
+	mux.HandleFunc("/cluster/members", s.handleClusterMembers)
+	mux.HandleFunc("/cluster/members/", s.handleClusterMembers)
+
+	// Echoes the caller's resolved Principal, for debugging an
+	// Authenticator configuration (see authmiddleware.go).
+	mux.HandleFunc("/auth/whoami", s.handleWhoami)
 
 	addr := fmt.Sprintf(":%d", s.port)
 	s.server = &http.Server{
@@ -94,18 +319,41 @@ func (s *HTTPCacheServer) Start() error {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	healthCtx, cancel := context.WithCancel(context.Background())
+	s.healthCheckCancel = cancel
+	s.peerHealth.Start(healthCtx, s.knownPeerAddrs)
+
+	if s.statsd != nil {
+		go s.statsdSampleLoop(healthCtx)
+	}
+
 	s.lgr.Info("HTTP cache server starting", logger.F("addr", addr))
 	return s.server.ListenAndServe()
 }
 
 // Stop gracefully shuts down the HTTP server
 func (s *HTTPCacheServer) Stop(ctx context.Context) error {
+	if s.healthCheckCancel != nil {
+		s.healthCheckCancel()
+	}
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
 	}
 	return nil
 }
 
+// handlePeerHealth exposes PeerHealth's current view of every known peer —
+// active-check status, last-check latency, consecutive failures — for
+// operator debugging (the same job Caddy's /debug/health endpoint does).
+func (s *HTTPCacheServer) handlePeerHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"peers": s.peerHealth.Status(),
+	}); err != nil {
+		s.lgr.Error("Failed to encode peer health response", logger.F("err", err))
+	}
+}
+
 // handleCacheRequest processes cache requests for URLs
 //
 // Request flow:
@@ -270,7 +518,7 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 					logger.F("debruijn_count", len(s.node.DeBruijnList())))
 
 				for _, db := range s.node.DeBruijnList() {
-					if db != nil && !db.ID.Equal(selfNode.ID) {
+					if db != nil && !db.ID.Equal(selfNode.ID) && s.peerHealth.IsHealthy(db.Addr) {
 						targetNode = db
 						s.lgr.Info("Using de Bruijn neighbor as Koorde fallback",
 							logger.F("url", url),
@@ -305,7 +553,7 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 				// Try finger table first
 				if chordNode, ok := s.node.(interface{ FingerList() []*domain.Node }); ok {
 					for _, finger := range chordNode.FingerList() {
-						if finger != nil && !finger.ID.Equal(selfNode.ID) {
+						if finger != nil && !finger.ID.Equal(selfNode.ID) && s.peerHealth.IsHealthy(finger.Addr) {
 							targetNode = finger
 							s.lgr.Info("Using finger table entry as Chord fallback",
 								logger.F("url", url),
@@ -318,7 +566,7 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 				// Fallback to successor list
 				if targetNode == nil || targetNode.ID.Equal(selfNode.ID) {
 					for _, succ := range s.node.SuccessorList() {
-						if succ != nil && !succ.ID.Equal(selfNode.ID) {
+						if succ != nil && !succ.ID.Equal(selfNode.ID) && s.peerHealth.IsHealthy(succ.Addr) {
 							targetNode = succ
 							s.lgr.Info("Using successor as Chord fallback",
 								logger.F("url", url),
@@ -335,7 +583,7 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 
 				// Try successor list as last resort
 				for _, succ := range s.node.SuccessorList() {
-					if succ != nil && !succ.ID.Equal(selfNode.ID) {
+					if succ != nil && !succ.ID.Equal(selfNode.ID) && s.peerHealth.IsHealthy(succ.Addr) {
 						targetNode = succ
 						break
 					}
@@ -375,42 +623,89 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// STEP 5: We ARE the responsible node - check local cache first
-	if entry, ok := s.cache.Get(url); ok {
-		s.lgr.Info("Cache HIT (local)",
-			logger.F("url", url),
-			logger.F("size_bytes", entry.Size),
-			logger.F("latency_ms", time.Since(start).Milliseconds()))
+	// STEP 5: We ARE the responsible node - check local cache first,
+	// honoring the freshness the origin response's Cache-Control/Expires
+	// implied (see cache.CacheControl and revalidate.go).
+	if entry, ok := s.cache.Peek(url); ok {
+		now := time.Now()
 
-		statusCode := entry.StatusCode
-		if statusCode < 100 {
-			statusCode = http.StatusOK
+		if now.Before(entry.Expiration) {
+			s.cache.Get(url) // bump hit/LRU bookkeeping now that we know it's fresh
+			s.lgr.Info("Cache HIT (local)",
+				logger.F("url", url),
+				logger.F("size_bytes", entry.Size),
+				logger.F("latency_ms", time.Since(start).Milliseconds()))
+			s.serveFromCache(w, entry, "HIT-LOCAL", start)
+			return
 		}
 
-		w.Header().Set("Content-Type", entry.ContentType)
-		w.Header().Set("X-Cache", "HIT-LOCAL")
-		w.Header().Set("X-Node-ID", s.node.Self().ID.ToHexString(true))
-		w.Header().Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
-		w.WriteHeader(statusCode)
-		w.Write(entry.Content)
-		return
+		if entry.CacheControl.HasStaleWhileRevalidate && now.Before(entry.StaleUntil) {
+			if s.startRevalidation(url) {
+				s.lgr.Info("Cache HIT (stale), revalidating in background",
+					logger.F("url", url), logger.F("latency_ms", time.Since(start).Milliseconds()))
+				go s.revalidateInBackground(url, entry.ETag, entry.LastModified)
+			} else {
+				s.lgr.Debug("Cache HIT (stale), revalidation already in flight",
+					logger.F("url", url), logger.F("latency_ms", time.Since(start).Milliseconds()))
+			}
+			s.serveFromCache(w, entry, "HIT-STALE", start)
+			return
+		}
+
+		if entry.ETag != "" || entry.LastModified != "" {
+			orig, notModified, err := s.fetchFromOrigin(r.Context(), url, entry.ETag, entry.LastModified)
+			switch {
+			case err != nil:
+				s.lgr.Warn("Synchronous revalidation failed, falling back to full origin fetch",
+					logger.F("url", url), logger.F("err", err))
+			case notModified:
+				ttl := orig.CacheControl.TTL(orig.Expires, originCacheTTL)
+				s.cache.RefreshValidated(url, ttl, orig.ETag, orig.LastModified, orig.CacheControl)
+				s.lgr.Info("Revalidated (304), serving refreshed cache entry",
+					logger.F("url", url), logger.F("latency_ms", time.Since(start).Milliseconds()))
+				s.serveFromCache(w, entry, "HIT-REVALIDATED", start)
+				return
+			default:
+				s.storeOriginResponse(url, orig)
+				s.lgr.Info("Revalidation fetched changed content",
+					logger.F("url", url), logger.F("latency_ms", time.Since(start).Milliseconds()))
+				w.Header().Set("Content-Type", orig.ContentType)
+				w.Header().Set("X-Cache", "MISS-REVALIDATED")
+				w.Header().Set("X-Node-ID", s.node.Self().ID.ToHexString(true))
+				w.Header().Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
+				w.WriteHeader(orig.StatusCode)
+				w.Write(orig.Content)
+				return
+			}
+		}
 	}
 
 	// STEP 6: Hotspot detection (only for responsible node)
 	isHot := s.hotspotDetector.RecordAccess(url)
 
 	if isHot {
-		// Hotspot detected - use random distribution strategy
-		avg, total, _ := s.hotspotDetector.GetStats(url)
-		s.lgr.Info("Hotspot detected, using random distribution",
-			logger.F("url", url),
-			logger.F("avg_rate", fmt.Sprintf("%.2f", avg)),
-			logger.F("total_requests", total))
+		// Hotspot detected - distribute load across known peers via the
+		// configured NodeSelector (random by default; see selector.go).
+		avg, lowerBound, _ := s.hotspotDetector.GetStats(url)
+
+		candidates := s.candidateNodes()
+		if len(candidates) > 0 {
+			target, err := s.selector.Select(r, candidates)
+			if err != nil {
+				s.lgr.Warn("Hotspot detected, node selection failed, fetching from origin",
+					logger.F("url", url), logger.F("err", err))
+			} else if target != "" && target != s.node.Self().Addr {
+				s.lgr.Info("Hotspot detected, distributing via node selector",
+					logger.F("url", url),
+					logger.F("avg_rate", fmt.Sprintf("%.2f", avg)),
+					logger.F("lower_bound_count", fmt.Sprintf("%.2f", lowerBound)),
+					logger.F("target", target))
 
-		randomNode := s.pickRandomNode()
-		if randomNode != "" && randomNode != s.node.Self().Addr {
-			s.proxyToNode(w, r, url, randomNode, "MISS-HOT", start)
-			return
+				done := s.peerStats.BeginRequest(target)
+				defer done()
+				s.proxyToNode(w, r, url, target, "MISS-HOT", start)
+				return
+			}
 		}
 		// Fall through to fetch from origin if no other node available
 	}
@@ -419,7 +714,7 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 	s.lgr.Info("I am responsible, fetching from origin",
 		logger.F("url", url))
 
-	content, contentType, statusCode, err := s.fetchFromOrigin(url)
+	orig, _, err := s.fetchFromOrigin(r.Context(), url, "", "")
 	if err != nil {
 		s.lgr.Error("Origin fetch failed",
 			logger.F("url", url),
@@ -428,36 +723,43 @@ func (s *HTTPCacheServer) handleCacheRequest(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// STEP 7: Cache the content locally
-	if err := s.cache.Put(url, content, contentType, originCacheTTL, statusCode); err != nil {
-		s.lgr.Warn("Failed to cache content",
-			logger.F("url", url),
-			logger.F("size", len(content)),
-			logger.F("err", err))
-		// Continue anyway - we can still serve the content
-	} else {
-		s.lgr.Info("Content cached successfully",
-			logger.F("url", url),
-			logger.F("size_bytes", len(content)))
-	}
+	// STEP 7: Cache the content locally, honoring Cache-Control
+	s.storeOriginResponse(url, orig)
 
 	// STEP 8: Return content to client
-	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Type", orig.ContentType)
 	w.Header().Set("X-Cache", "MISS-ORIGIN")
 	w.Header().Set("X-Node-ID", s.node.Self().ID.ToHexString(true))
 	w.Header().Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
-	w.WriteHeader(statusCode)
-	w.Write(content)
+	w.WriteHeader(orig.StatusCode)
+	w.Write(orig.Content)
 
 	s.lgr.Info("Request completed",
 		logger.F("url", url),
 		logger.F("total_latency_ms", time.Since(start).Milliseconds()),
-		logger.F("content_size", len(content)))
+		logger.F("content_size", len(orig.Content)))
+}
+
+// candidateNodes returns every peer address this node currently knows
+// about AND that PeerHealth currently considers healthy, for the
+// NodeSelector to choose among during hotspot distribution. The STEP 4
+// fallback loops apply the same PeerHealth filter to their own lists.
+func (s *HTTPCacheServer) candidateNodes() []string {
+	known := s.knownPeerAddrs()
+	nodes := make([]string, 0, len(known))
+	for _, addr := range known {
+		if s.peerHealth.IsHealthy(addr) {
+			nodes = append(nodes, addr)
+		}
+	}
+	return nodes
 }
 
-// pickRandomNode selects a random node from the cluster
+// knownPeerAddrs returns every peer address this node currently knows
+// about, regardless of health, so PeerHealth's active checker can keep
+// probing (and recovering) addresses that candidateNodes is filtering out.
 // Uses successor list + de Bruijn list (Koorde) or finger table (Chord) as source of known nodes
-func (s *HTTPCacheServer) pickRandomNode() string {
+func (s *HTTPCacheServer) knownPeerAddrs() []string {
 	// Collect all known nodes
 	allNodes := make(map[string]bool)
 
@@ -504,185 +806,63 @@ func (s *HTTPCacheServer) pickRandomNode() string {
 		}
 	}
 
-	if len(nodes) == 0 {
-		// Fallback to self if no other nodes known
-		s.lgr.Warn("No other nodes known, using self for random selection")
-		return s.node.Self().Addr
-	}
-
-	// Random selection
-	return nodes[rand.Intn(len(nodes))]
+	return nodes
 }
 
-// proxyToNode forwards the request to another node via HTTP
-func (s *HTTPCacheServer) proxyToNode(
-	w http.ResponseWriter,
-	r *http.Request,
-	url string,
-	nodeAddr string, // e.g., "10.0.1.89:4000" (gRPC addr)
-	cacheStatus string,
-	start time.Time,
-) {
-	// Extract host and gRPC port from "host:port" (gRPC address format)
-	host, portStr, err := net.SplitHostPort(nodeAddr)
-	if err != nil {
-		// nodeAddr might already be just "host" - can't determine HTTP port
-		s.lgr.Error("Proxy failed: cannot parse node address",
-			logger.F("node_addr", nodeAddr),
-			logger.F("err", err))
-		http.Error(w, fmt.Sprintf("invalid node address: %s", nodeAddr), http.StatusInternalServerError)
-		return
-	}
-
-	// Parse gRPC port
-	var grpcPort int
-	_, err = fmt.Sscanf(portStr, "%d", &grpcPort)
-	if err != nil {
-		s.lgr.Error("Proxy failed: cannot parse gRPC port",
-			logger.F("node_addr", nodeAddr),
-			logger.F("port_str", portStr),
-			logger.F("err", err))
-		http.Error(w, fmt.Sprintf("invalid gRPC port: %s", portStr), http.StatusInternalServerError)
-		return
-	}
-
-	// Calculate HTTP port using the configured offset (derived from this node)
-	httpPort := grpcPort + s.grpcToHTTPPortOffset
-
-	// Construct HTTP URL (using calculated HTTP port, not self's port)
-	proxyURL := fmt.Sprintf("http://%s:%d/cache?url=%s", host, httpPort, url)
-
-	s.lgr.Debug("Proxying request",
-		logger.F("url", url),
-		logger.F("proxy_url", proxyURL),
-		logger.F("target_node", nodeAddr))
-
-	// Create request with forwarding headers
-	req, err := http.NewRequestWithContext(r.Context(), "GET", proxyURL, nil)
-	if err != nil {
-		s.lgr.Error("Failed to create proxy request",
-			logger.F("proxy_url", proxyURL),
-			logger.F("err", err))
-		http.Error(w, "proxy request creation failed", http.StatusInternalServerError)
-		return
-	}
-
-	// Add headers to prevent loops and enable optimizations
-	req.Header.Set("X-Forwarded-From", s.node.Self().Addr)
-	req.Header.Set("X-Is-Responsible", "true") // Tell target it's responsible
-	req.Header.Set("X-Original-Request-Time", start.Format(time.RFC3339Nano))
-
-	// Send request
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		s.lgr.Error("Proxy request failed",
-			logger.F("proxy_url", proxyURL),
-			logger.F("err", err))
-		http.Error(w, fmt.Sprintf("proxy failed: %v", err), http.StatusBadGateway)
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		s.lgr.Error("Failed to read proxy response",
-			logger.F("proxy_url", proxyURL),
-			logger.F("err", err))
-		http.Error(w, "failed to read proxy response", http.StatusInternalServerError)
-		return
-	}
-
-	// Determine content type once for headers and optional caching
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
-	// Opportunistically cache successful proxy responses locally so future
-	// requests avoid another remote hop.
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		if err := s.cache.Put(url, content, contentType, nearCacheTTL, resp.StatusCode); err != nil {
-			s.lgr.Warn("Failed to cache proxied content",
-				logger.F("url", url),
-				logger.F("size", len(content)),
-				logger.F("err", err))
-		} else {
-			s.lgr.Debug("Cached proxied content locally",
-				logger.F("url", url),
-				logger.F("size_bytes", len(content)))
-		}
-	}
-
-	// Forward response to client
-	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("X-Cache", cacheStatus)
-	w.Header().Set("X-Responsible-Node", nodeAddr)
-	w.Header().Set("X-Entry-Node", s.node.Self().Addr)
-	w.Header().Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
-
-	// Copy additional headers from proxy response
-	if cacheHdr := resp.Header.Get("X-Cache"); cacheHdr != "" {
-		w.Header().Set("X-Cache-Origin", cacheHdr)
-	}
-
-	w.WriteHeader(resp.StatusCode)
-	w.Write(content)
-
-	s.lgr.Info("Request proxied successfully",
-		logger.F("url", url),
-		logger.F("target_node", nodeAddr),
-		logger.F("total_latency_ms", time.Since(start).Milliseconds()),
-		logger.F("size_bytes", len(content)))
+// proxyToNode forwards the request to another node's HTTP cache endpoint.
+// See proxy.go for the streaming implementation.
+
+// originResponse bundles what fetchFromOrigin learned from an origin
+// response, including the RFC 7234 validators/directives needed to decide
+// whether (and for how long) it may be cached, and to later revalidate it.
+type originResponse struct {
+	Content      []byte
+	ContentType  string
+	StatusCode   int
+	ETag         string
+	LastModified string
+	Expires      string
+	CacheControl cache.CacheControl
 }
 
-// fetchFromOrigin fetches content from the original URL
-func (s *HTTPCacheServer) fetchFromOrigin(url string) ([]byte, string, int, error) {
-	s.lgr.Debug("Fetching from origin", logger.F("url", url))
+// fetchFromOrigin fetches content from the original URL. If etag or
+// lastModified is non-empty, the request carries the matching conditional
+// header (If-None-Match / If-Modified-Since); a 304 response is reported
+// via the notModified return with a nil *originResponse.
+func (s *HTTPCacheServer) fetchFromOrigin(ctx context.Context, rawURL string, etag, lastModified string) (*originResponse, bool, error) {
+	s.lgr.Debug("Fetching from origin", logger.F("url", rawURL), logger.F("conditional", etag != "" || lastModified != ""))
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Allow up to 10 redirects
-			if len(via) >= 10 {
-				return fmt.Errorf("too many redirects")
-			}
-			return nil
-		},
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, "", 0, fmt.Errorf("origin request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	statusCode := resp.StatusCode
-	if statusCode < 200 || statusCode >= 300 {
-		return nil, "", statusCode, fmt.Errorf("origin returned status %d", statusCode)
-	}
-
-	content, err := io.ReadAll(resp.Body)
+	resp, notModified, err := s.originFetcher.Fetch(ctx, rawURL, etag, lastModified)
 	if err != nil {
-		return nil, "", statusCode, fmt.Errorf("failed to read origin response: %w", err)
+		return nil, false, err
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+	if notModified {
+		s.lgr.Debug("Origin revalidation returned 304", logger.F("url", rawURL))
+		return &originResponse{
+			StatusCode:   resp.StatusCode,
+			ETag:         resp.ETag,
+			LastModified: resp.LastModified,
+			Expires:      resp.Expires,
+			CacheControl: cache.ParseCacheControl(resp.CacheControl),
+		}, true, nil
 	}
 
 	s.lgr.Info("Origin fetch successful",
-		logger.F("url", url),
-		logger.F("size_bytes", len(content)),
-		logger.F("content_type", contentType),
-		logger.F("status_code", statusCode))
-
-	return content, contentType, statusCode, nil
+		logger.F("url", rawURL),
+		logger.F("size_bytes", len(resp.Content)),
+		logger.F("content_type", resp.ContentType),
+		logger.F("status_code", resp.StatusCode))
+
+	return &originResponse{
+		Content:      resp.Content,
+		ContentType:  resp.ContentType,
+		StatusCode:   resp.StatusCode,
+		ETag:         resp.ETag,
+		LastModified: resp.LastModified,
+		Expires:      resp.Expires,
+		CacheControl: cache.ParseCacheControl(resp.CacheControl),
+	}, false, nil
 }
 
 // handleMetrics returns cache and hotspot statistics as JSON
@@ -713,92 +893,44 @@ func (s *HTTPCacheServer) handleMetrics(w http.ResponseWriter, r *http.Request)
 			"utilization":    cacheMetrics.Utilization,
 		},
 		"hotspots": map[string]interface{}{
-			"count": len(hotURLs),
-			"urls":  hotURLs,
+			"count":              len(hotURLs),
+			"urls":               hotURLs,
+			"replication_factor": s.replicationFactor,
+			"replicas":           s.allHotReplicas(),
+		},
+		"cache_events": map[string]interface{}{
+			"dropped_events": s.cacheEvents.DroppedEvents(),
 		},
 		"routing": map[string]interface{}{
 			"successor_count": succCount,
 			"debruijn_count":  deBruijnCount,
 			"has_predecessor": hasPred,
 			"stats": map[string]interface{}{
-				"protocol":                  routingStats.Protocol,
-				"de_bruijn_success":         routingStats.DeBruijnSuccessCount,
-				"de_bruijn_failures":        routingStats.DeBruijnFailureCount,
-				"successor_fallbacks":       routingStats.SuccessorFallbackCount,
-				"avg_de_bruijn_success_ms":  routingStats.AvgDeBruijnSuccessLatencyMs,
-				"avg_de_bruijn_failure_ms":  routingStats.AvgDeBruijnFailureLatencyMs,
-				"avg_successor_fallback_ms": routingStats.AvgSuccessorFallbackLatency,
+				"protocol":             routingStats.Protocol,
+				"de_bruijn_success":    routingStats.DeBruijnSuccessCount,
+				"de_bruijn_failures":   routingStats.DeBruijnFailureCount,
+				"successor_fallbacks":  routingStats.SuccessorFallbackCount,
+				"de_bruijn_success_ms": percentileSummary{routingStats.DeBruijnSuccessP50Ms, routingStats.DeBruijnSuccessP90Ms, routingStats.DeBruijnSuccessP99Ms, routingStats.DeBruijnSuccessP999Ms},
+				"de_bruijn_failure_ms": percentileSummary{routingStats.DeBruijnFailureP50Ms, routingStats.DeBruijnFailureP90Ms, routingStats.DeBruijnFailureP99Ms, routingStats.DeBruijnFailureP999Ms},
+				"successor_fallback_ms": percentileSummary{routingStats.SuccessorFallbackP50Ms, routingStats.SuccessorFallbackP90Ms, routingStats.SuccessorFallbackP99Ms, routingStats.SuccessorFallbackP999Ms},
 			},
 		},
+		"peer_health": s.peerHealth.Status(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// handleHealth returns node health status
-func (s *HTTPCacheServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	// Check if node is properly initialized
-	healthy := true
-	status := "READY"
-	self := s.node.Self()
-	nodeID := ""
-	if self == nil {
-		healthy = false
-		status = "NOT_INITIALIZED"
-	} else {
-		nodeID = self.ID.ToHexString(true)
-	}
-
-	succList := s.node.SuccessorList()
-	successorReady := len(succList) > 0
-	if !successorReady {
-		healthy = false
-		if status == "READY" {
-			status = "NOT_INITIALIZED"
-		}
-	}
-
-	routingStats := s.node.RoutingMetrics()
-	deBruijnList := s.node.DeBruijnList()
-	requiredDeBruijn := s.node.Space().GraphGrade
-	deBruijnCount := len(deBruijnList)
-	deBruijnReady := true
-	if routingStats.Protocol == "koorde" {
-		// Require at least 1 de Bruijn neighbor for readiness (full degree may exceed cluster size)
-		deBruijnReady = deBruijnCount >= 1
-		if !deBruijnReady {
-			healthy = false
-			status = "DEBRUIJN_NOT_READY"
-		}
-	}
-
-	response := map[string]interface{}{
-		"healthy": healthy,
-		"status":  status,
-		"node_id": nodeID,
-		"details": map[string]interface{}{
-			"protocol":           routingStats.Protocol,
-			"successor_ready":    successorReady,
-			"successor_count":    len(succList),
-			"de_bruijn_ready":    deBruijnReady,
-			"de_bruijn_count":    deBruijnCount,
-			"required_de_bruijn": requiredDeBruijn,
-		},
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-
-	if !healthy {
-		w.WriteHeader(http.StatusServiceUnavailable)
-	}
+// handleHealth, handleLivez, and handleReadyz are implemented in
+// healthchecks.go, backed by healthcheck.Registry.
 
-	json.NewEncoder(w).Encode(response)
-}
-
-// handleClusterRemove handles membership update requests for simple hash nodes.
+// handleClusterRemove handles membership update requests for protocols
+// implementing dht.MembershipManager.
 // POST /cluster/remove?node=localhost:4003
-// This endpoint only works for simple hash protocol nodes.
+//
+// Deprecated: prefer DELETE /cluster/members/{id} (see handleClusterMembers),
+// which works the same way but reports a node ID instead of a raw address.
 func (s *HTTPCacheServer) handleClusterRemove(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed, use POST", http.StatusMethodNotAllowed)
@@ -807,34 +939,32 @@ func (s *HTTPCacheServer) handleClusterRemove(w http.ResponseWriter, r *http.Req
 
 	nodeAddr := r.URL.Query().Get("node")
 	if nodeAddr == "" {
-		http.Error(w, "missing 'node' query parameter", http.StatusBadRequest)
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing 'node' query parameter", nil)
 		return
 	}
 
-	// Type assertion to check if this is a simple hash node with RemoveNode method
-	type nodeRemover interface {
-		RemoveNode(addr string) error
-	}
-
-	remover, ok := s.node.(nodeRemover)
+	mm, ok := s.node.(dht.MembershipManager)
 	if !ok {
-		http.Error(w, "cluster membership update only supported for simple hash protocol", http.StatusBadRequest)
+		s.writeJSONError(w, r, http.StatusNotImplemented, ErrCodeProtocolUnsupported, "cluster membership update not supported for this protocol", nil)
+		return
+	}
+	if sa, ok := mm.(dht.StabilizationAware); ok && sa.Stabilizing() {
+		s.writeJSONError(w, r, http.StatusConflict, ErrCodeMembershipBusy, "node is mid-stabilization, retry the membership update", nil)
 		return
 	}
 
-	// Remove the node from cluster membership
-	if err := remover.RemoveNode(nodeAddr); err != nil {
+	if err := mm.RemoveNode(nodeAddr); err != nil {
 		s.lgr.Warn("Failed to remove node from cluster",
 			logger.F("node", nodeAddr),
 			logger.F("err", err))
-		http.Error(w, fmt.Sprintf("failed to remove node: %v", err), http.StatusInternalServerError)
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to remove node", err)
 		return
 	}
 
+	s.membershipChangesTotal.WithLabelValues("remove").Inc()
 	s.lgr.Info("Node removed from cluster membership",
 		logger.F("removed_node", nodeAddr))
 
-	// Return success response
 	response := map[string]interface{}{
 		"success":      true,
 		"removed_node": nodeAddr,
@@ -845,9 +975,12 @@ func (s *HTTPCacheServer) handleClusterRemove(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(response)
 }
 
-// handleClusterAdd handles node addition requests for simple hash nodes.
+// handleClusterAdd handles node addition requests for protocols
+// implementing dht.MembershipManager.
 // POST /cluster/add?node=localhost:4003
-// This endpoint only works for simple hash protocol nodes.
+//
+// Deprecated: prefer POST /cluster/members (see handleClusterMembers), which
+// works the same way but returns the assigned node ID.
 func (s *HTTPCacheServer) handleClusterAdd(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed, use POST", http.StatusMethodNotAllowed)
@@ -856,34 +989,32 @@ func (s *HTTPCacheServer) handleClusterAdd(w http.ResponseWriter, r *http.Reques
 
 	nodeAddr := r.URL.Query().Get("node")
 	if nodeAddr == "" {
-		http.Error(w, "missing 'node' query parameter", http.StatusBadRequest)
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing 'node' query parameter", nil)
 		return
 	}
 
-	// Type assertion to check if this is a simple hash node with AddNode method
-	type nodeAdder interface {
-		AddNode(addr string) error
-	}
-
-	adder, ok := s.node.(nodeAdder)
+	mm, ok := s.node.(dht.MembershipManager)
 	if !ok {
-		http.Error(w, "cluster membership update only supported for simple hash protocol", http.StatusBadRequest)
+		s.writeJSONError(w, r, http.StatusNotImplemented, ErrCodeProtocolUnsupported, "cluster membership update not supported for this protocol", nil)
+		return
+	}
+	if sa, ok := mm.(dht.StabilizationAware); ok && sa.Stabilizing() {
+		s.writeJSONError(w, r, http.StatusConflict, ErrCodeMembershipBusy, "node is mid-stabilization, retry the membership update", nil)
 		return
 	}
 
-	// Add the node to cluster membership
-	if err := adder.AddNode(nodeAddr); err != nil {
+	if err := mm.AddNode(nodeAddr); err != nil {
 		s.lgr.Warn("Failed to add node to cluster",
 			logger.F("node", nodeAddr),
 			logger.F("err", err))
-		http.Error(w, fmt.Sprintf("failed to add node: %v", err), http.StatusInternalServerError)
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to add node", err)
 		return
 	}
 
+	s.membershipChangesTotal.WithLabelValues("add").Inc()
 	s.lgr.Info("Node added to cluster membership",
 		logger.F("added_node", nodeAddr))
 
-	// Return success response
 	response := map[string]interface{}{
 		"success":    true,
 		"added_node": nodeAddr,
@@ -894,6 +1025,179 @@ func (s *HTTPCacheServer) handleClusterAdd(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(response)
 }
 
+// membershipMember is the JSON shape of one entry in a /cluster/members
+// response, modeled on etcd's /v2/members.
+type membershipMember struct {
+	ID      string `json:"id"`
+	PeerURL string `json:"peerURL"`
+	Role    string `json:"role,omitempty"`
+}
+
+// membershipAddRequest is the JSON body of POST /cluster/members.
+type membershipAddRequest struct {
+	PeerURL string `json:"peerURL"`
+	Role    string `json:"role,omitempty"`
+}
+
+// handleClusterMembers implements an etcd-/v2/members-style admin API over
+// dht.MembershipManager:
+//
+//	GET    /cluster/members      -> full known membership view, any protocol
+//	POST   /cluster/members      -> {"peerURL": "...", "role": "..."}, admits a node
+//	DELETE /cluster/members/{id} -> evicts a node by its assigned ID
+//
+// GET works against any DHTNode: it reports self plus whatever predecessor,
+// successor, and de Bruijn neighbors are currently known, which is the best
+// "membership view" available even for protocols (Chord/Koorde) that
+// self-heal instead of taking edits. POST/DELETE require
+// dht.MembershipManager and respond 501 Not Implemented when the underlying
+// protocol doesn't support admin-driven edits, or 409 Conflict when the
+// manager reports it's mid-stabilization (see dht.StabilizationAware).
+func (s *HTTPCacheServer) handleClusterMembers(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/cluster/members":
+		s.handleListMembers(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/cluster/members":
+		s.requireRole(RoleAdmin, s.handleAddMember)(w, r)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/cluster/members/"):
+		s.requireRole(RoleAdmin, s.handleRemoveMember)(w, r)
+	default:
+		s.writeJSONError(w, r, http.StatusMethodNotAllowed, ErrCodeInvalidRequest, "method not allowed", nil)
+	}
+}
+
+func (s *HTTPCacheServer) handleListMembers(w http.ResponseWriter, r *http.Request) {
+	seen := make(map[string]bool)
+	var members []membershipMember
+
+	add := func(n *domain.Node) {
+		if n == nil || seen[n.Addr] {
+			return
+		}
+		seen[n.Addr] = true
+		members = append(members, membershipMember{
+			ID:      n.ID.ToHexString(true),
+			PeerURL: n.Addr,
+			Role:    "member",
+		})
+	}
+
+	if mm, ok := s.node.(dht.MembershipManager); ok {
+		for _, n := range mm.ClusterNodes() {
+			add(n)
+		}
+	} else {
+		add(s.node.Self())
+		add(s.node.Predecessor())
+		for _, n := range s.node.SuccessorList() {
+			add(n)
+		}
+		for _, n := range s.node.DeBruijnList() {
+			add(n)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"members": members})
+}
+
+func (s *HTTPCacheServer) handleAddMember(w http.ResponseWriter, r *http.Request) {
+	var req membershipAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid request body", err)
+		return
+	}
+	if req.PeerURL == "" {
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing 'peerURL' field", nil)
+		return
+	}
+
+	mm, ok := s.node.(dht.MembershipManager)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusNotImplemented, ErrCodeProtocolUnsupported, "cluster membership update not supported for this protocol", nil)
+		return
+	}
+	if sa, ok := mm.(dht.StabilizationAware); ok && sa.Stabilizing() {
+		s.writeJSONError(w, r, http.StatusConflict, ErrCodeMembershipBusy, "node is mid-stabilization, retry the membership update", nil)
+		return
+	}
+
+	if err := mm.AddNode(req.PeerURL); err != nil {
+		s.lgr.Warn("Failed to add node to cluster",
+			logger.F("peer_url", req.PeerURL), logger.F("err", err))
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to add node", err)
+		return
+	}
+
+	role := req.Role
+	if role == "" {
+		role = "member"
+	}
+	id := s.node.Space().NewIdFromString(req.PeerURL)
+
+	s.membershipChangesTotal.WithLabelValues("add").Inc()
+	s.lgr.Info("Node added to cluster membership",
+		logger.F("peer_url", req.PeerURL), logger.F("id", id.ToHexString(true)))
+
+	writeJSON(w, http.StatusOK, membershipMember{
+		ID:      id.ToHexString(true),
+		PeerURL: req.PeerURL,
+		Role:    role,
+	})
+}
+
+func (s *HTTPCacheServer) handleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/cluster/members/")
+	if id == "" {
+		s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing member id", nil)
+		return
+	}
+
+	mm, ok := s.node.(dht.MembershipManager)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusNotImplemented, ErrCodeProtocolUnsupported, "cluster membership update not supported for this protocol", nil)
+		return
+	}
+	if sa, ok := mm.(dht.StabilizationAware); ok && sa.Stabilizing() {
+		s.writeJSONError(w, r, http.StatusConflict, ErrCodeMembershipBusy, "node is mid-stabilization, retry the membership update", nil)
+		return
+	}
+
+	var addr string
+	for _, n := range mm.ClusterNodes() {
+		if n.ID.ToHexString(true) == id {
+			addr = n.Addr
+			break
+		}
+	}
+	if addr == "" {
+		s.writeJSONError(w, r, http.StatusNotFound, ErrCodeNodeNotFound, fmt.Sprintf("no known member with id %q", id), nil)
+		return
+	}
+
+	if err := mm.RemoveNode(addr); err != nil {
+		s.lgr.Warn("Failed to remove node from cluster",
+			logger.F("id", id), logger.F("peer_url", addr), logger.F("err", err))
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "failed to remove node", err)
+		return
+	}
+
+	s.membershipChangesTotal.WithLabelValues("remove").Inc()
+	s.lgr.Info("Node removed from cluster membership",
+		logger.F("id", id), logger.F("peer_url", addr))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// percentileSummary is the compact four-number view of a dht.RoutingMetrics
+// latency histogram shown in /metrics; BucketCounts is omitted here since the
+// raw histogram is meant for plotting, not for a human skimming /metrics.
+type percentileSummary struct {
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P99  float64 `json:"p99_ms"`
+	P999 float64 `json:"p999_ms"`
+}
+
 // handleDebug returns detailed routing table information
 func (s *HTTPCacheServer) handleDebug(w http.ResponseWriter, r *http.Request) {
 	self := s.node.Self()
@@ -953,6 +1257,10 @@ func (s *HTTPCacheServer) handleDebug(w http.ResponseWriter, r *http.Request) {
 
 	response["routing_table_bytes"] = routingTableBytes
 
+	// Hot-URL replica placement (see hotreplicate.go).
+	response["hot_replicas"] = s.allHotReplicas()
+	response["replication_factor"] = s.replicationFactor
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Stable, machine-readable error codes returned in httpError.Code, so
+// programmatic clients can switch on a specific failure mode instead of
+// pattern-matching a free-form message.
+const (
+	ErrCodeInvalidRequest      = "INVALID_REQUEST"
+	ErrCodeProtocolUnsupported = "PROTOCOL_UNSUPPORTED"
+	ErrCodeNodeNotFound        = "NODE_NOT_FOUND"
+	ErrCodeMembershipBusy      = "MEMBERSHIP_BUSY"
+	ErrCodeInternal            = "INTERNAL"
+	ErrCodeUnauthorized        = "UNAUTHORIZED"
+	ErrCodeForbidden           = "FORBIDDEN"
+)
+
+// httpError is the JSON envelope written by writeJSONError, modeled on
+// etcd's httptypes.HTTPError: a stable machine-readable Code alongside the
+// human-readable Message, the request Path for correlation, the responding
+// NodeID, and Cause — the wrapped-error chain (via errors.Unwrap) behind
+// Message, when the error passed in carries one.
+type httpError struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Path    string   `json:"path"`
+	NodeID  string   `json:"node_id,omitempty"`
+	Cause   []string `json:"cause,omitempty"`
+}
+
+// writeJSON writes body as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeJSONError writes a structured httpError response in place of
+// http.Error's plain text, so clients get a stable code field and
+// operators get the request path, responding node, and full cause chain
+// in one uniform shape. cause may be nil when there's no underlying error
+// to unwrap (e.g. a bad request with nothing to chain).
+func (s *HTTPCacheServer) writeJSONError(w http.ResponseWriter, r *http.Request, status int, code, message string, cause error) {
+	herr := httpError{
+		Code:    code,
+		Message: message,
+		Path:    r.URL.Path,
+	}
+	if self := s.node.Self(); self != nil {
+		herr.NodeID = self.ID.ToHexString(true)
+	}
+	for err := cause; err != nil; err = errors.Unwrap(err) {
+		herr.Cause = append(herr.Cause, err.Error())
+	}
+	writeJSON(w, status, herr)
+}
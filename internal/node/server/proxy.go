@@ -0,0 +1,174 @@
+package server
+
+import (
+	"KoordeDHT/internal/logger"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultProxyFlushInterval is how often the reverse proxy flushes
+	// buffered bytes to the client. -1 flushes after every write, which
+	// SSE/WebSocket-like responses need.
+	defaultProxyFlushInterval = 100 * time.Millisecond
+
+	// defaultProxyTeeCacheMaxBytes caps how large a proxied body may be
+	// for the opportunistic tee-into-cache optimization to apply.
+	defaultProxyTeeCacheMaxBytes = 8 << 20 // 8 MiB
+)
+
+// httpURLFor converts a peer's gRPC address (e.g. "10.0.1.89:4000") into
+// the *url.URL of that peer's HTTP cache server, using this node's own
+// configured grpcToHTTPPortOffset — the same fixed offset every node in
+// the cluster is assumed to run with.
+func (s *HTTPCacheServer) httpURLFor(nodeAddr string) (*url.URL, error) {
+	host, portStr, err := net.SplitHostPort(nodeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node address %q: %w", nodeAddr, err)
+	}
+
+	grpcPort, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gRPC port %q in node address %q: %w", portStr, nodeAddr, err)
+	}
+
+	httpPort := grpcPort + s.grpcToHTTPPortOffset
+	return &url.URL{Scheme: "http", Host: fmt.Sprintf("%s:%d", host, httpPort)}, nil
+}
+
+// proxyToNode forwards the request to another node's HTTP cache endpoint
+// using a streaming net/http/httputil.ReverseProxy, so multi-MB assets,
+// chunked responses, and SSE streams pass through without being buffered
+// in memory on this hop.
+func (s *HTTPCacheServer) proxyToNode(
+	w http.ResponseWriter,
+	r *http.Request,
+	cacheURL string,
+	nodeAddr string, // e.g., "10.0.1.89:4000" (gRPC addr)
+	cacheStatus string,
+	start time.Time,
+) {
+	target, err := s.httpURLFor(nodeAddr)
+	if err != nil {
+		s.lgr.Error("Proxy failed: cannot resolve node's HTTP address",
+			logger.F("node_addr", nodeAddr),
+			logger.F("err", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.lgr.Debug("Proxying request",
+		logger.F("url", cacheURL),
+		logger.F("target", target.String()),
+		logger.F("target_node", nodeAddr))
+
+	proxy := &httputil.ReverseProxy{
+		FlushInterval: s.proxyFlushInterval,
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = "/cache"
+			req.URL.RawQuery = url.Values{"url": {cacheURL}}.Encode()
+			req.Host = target.Host
+
+			// Headers that prevent forwarding loops and let the target
+			// skip its own responsibility lookup.
+			req.Header.Set("X-Forwarded-From", s.node.Self().Addr)
+			req.Header.Set("X-Is-Responsible", "true")
+			req.Header.Set("X-Original-Request-Time", start.Format(time.RFC3339Nano))
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			s.peerHealth.RecordProxySuccess(nodeAddr)
+
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				s.teeIntoCache(cacheURL, resp)
+			}
+
+			if cacheHdr := resp.Header.Get("X-Cache"); cacheHdr != "" {
+				resp.Header.Set("X-Cache-Origin", cacheHdr)
+			}
+			resp.Header.Set("X-Cache", cacheStatus)
+			resp.Header.Set("X-Responsible-Node", nodeAddr)
+			resp.Header.Set("X-Entry-Node", s.node.Self().Addr)
+			resp.Header.Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			s.peerHealth.RecordProxyFailure(nodeAddr)
+			s.lgr.Error("Proxy request failed",
+				logger.F("target", target.String()),
+				logger.F("err", err))
+			http.Error(w, fmt.Sprintf("proxy failed: %v", err), http.StatusBadGateway)
+		},
+	}
+
+	proxy.ServeHTTP(w, r)
+
+	s.lgr.Info("Request proxied successfully",
+		logger.F("url", cacheURL),
+		logger.F("target_node", nodeAddr),
+		logger.F("total_latency_ms", time.Since(start).Milliseconds()))
+}
+
+// teeIntoCache wraps resp.Body so that, as the reverse proxy streams it to
+// the client, a copy is captured into the local cache via an
+// io.TeeReader-backed io.Pipe — but only if the body stays under
+// proxyTeeCacheMaxBytes. The client-facing copy is never buffered or
+// delayed: the tee drains concurrently on its own goroutine, so a slow (or
+// abandoned) cache.Put can't stall the response, and a body that exceeds
+// the threshold is simply not cached rather than aborting the proxy.
+func (s *HTTPCacheServer) teeIntoCache(cacheURL string, resp *http.Response) {
+	pr, pw := io.Pipe()
+	originalBody := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.TeeReader(originalBody, pw),
+		Closer: originalBody,
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	statusCode := resp.StatusCode
+	maxBytes := s.proxyTeeCacheMaxBytes
+
+	go func() {
+		defer pw.Close()
+
+		var buf bytes.Buffer
+		n, err := io.Copy(&buf, io.LimitReader(pr, maxBytes+1))
+		// Drain whatever the client copy still writes into the pipe past
+		// the threshold, so TeeReader.Read on the response body never
+		// blocks waiting for us.
+		io.Copy(io.Discard, pr)
+
+		if err != nil {
+			s.lgr.Debug("teeIntoCache: tee read failed, skipping cache",
+				logger.F("url", cacheURL), logger.F("err", err))
+			return
+		}
+		if n > maxBytes {
+			s.lgr.Debug("teeIntoCache: body exceeds tee cache threshold, skipping cache",
+				logger.F("url", cacheURL), logger.F("threshold_bytes", maxBytes))
+			return
+		}
+
+		if err := s.cache.Put(cacheURL, buf.Bytes(), contentType, nearCacheTTL, statusCode); err != nil {
+			s.lgr.Warn("Failed to cache proxied content",
+				logger.F("url", cacheURL), logger.F("size", buf.Len()), logger.F("err", err))
+			return
+		}
+		s.lgr.Debug("Cached proxied content locally (streamed)",
+			logger.F("url", cacheURL), logger.F("size_bytes", buf.Len()))
+	}()
+}
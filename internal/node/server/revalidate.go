@@ -0,0 +1,109 @@
+package server
+
+import (
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/cache"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// serveFromCache writes a cached entry to w, tagging the response with
+// cacheStatus (e.g. "HIT-LOCAL", "HIT-STALE", "HIT-REVALIDATED") so clients
+// and logs can tell which path served the request.
+func (s *HTTPCacheServer) serveFromCache(w http.ResponseWriter, entry *cache.Entry, cacheStatus string, start time.Time) {
+	statusCode := entry.StatusCode
+	if statusCode < 100 {
+		statusCode = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", entry.ContentType)
+	w.Header().Set("X-Cache", cacheStatus)
+	w.Header().Set("X-Node-ID", s.node.Self().ID.ToHexString(true))
+	w.Header().Set("X-Latency-Ms", fmt.Sprintf("%.2f", time.Since(start).Seconds()*1000))
+	w.WriteHeader(statusCode)
+	w.Write(entry.Content)
+}
+
+// storeOriginResponse caches a freshly-fetched origin response, deriving
+// its TTL from s-maxage/max-age/Expires (falling back to originCacheTTL)
+// and refusing to store responses marked no-store or private, per
+// RFC 7234. Returns false if the response wasn't cacheable.
+func (s *HTTPCacheServer) storeOriginResponse(url string, orig *originResponse) bool {
+	if !orig.CacheControl.Cacheable() {
+		s.lgr.Debug("Origin response not cacheable, serving without storing",
+			logger.F("url", url),
+			logger.F("no_store", orig.CacheControl.NoStore),
+			logger.F("private", orig.CacheControl.Private))
+		return false
+	}
+
+	ttl := orig.CacheControl.TTL(orig.Expires, originCacheTTL)
+	if orig.CacheControl.NoCache {
+		// no-cache allows storage but forbids serving without
+		// revalidation first; modeling that as an already-stale entry
+		// means the next request always revalidates before using it.
+		ttl = 0
+	}
+
+	if err := s.cache.PutValidated(url, orig.Content, orig.ContentType, ttl, orig.StatusCode,
+		orig.ETag, orig.LastModified, orig.CacheControl); err != nil {
+		s.lgr.Warn("Failed to cache origin content",
+			logger.F("url", url), logger.F("size", len(orig.Content)), logger.F("err", err))
+		return false
+	}
+
+	s.lgr.Info("Content cached successfully",
+		logger.F("url", url), logger.F("size_bytes", len(orig.Content)), logger.F("ttl", ttl))
+	return true
+}
+
+// startRevalidation claims url for a background revalidation, returning
+// true if the caller won the claim (and so should spawn
+// revalidateInBackground) or false if one is already in flight for url —
+// the in-flight guard behind revalidateInBackground, so a hot URL's
+// hundreds of concurrent stale hits during its SWR window share a single
+// origin fetch instead of each starting their own.
+func (s *HTTPCacheServer) startRevalidation(url string) bool {
+	s.revalidatingMu.Lock()
+	defer s.revalidatingMu.Unlock()
+	if _, inFlight := s.revalidating[url]; inFlight {
+		return false
+	}
+	s.revalidating[url] = struct{}{}
+	return true
+}
+
+// endRevalidation releases url's claim taken by startRevalidation, once its
+// background revalidation has finished.
+func (s *HTTPCacheServer) endRevalidation(url string) {
+	s.revalidatingMu.Lock()
+	delete(s.revalidating, url)
+	s.revalidatingMu.Unlock()
+}
+
+// revalidateInBackground is the stale-while-revalidate path: it reissues a
+// conditional GET for url and refreshes (or replaces) the cached entry,
+// without anyone waiting on the result — the client that triggered it was
+// already served the stale copy. Callers must have won url's claim via
+// startRevalidation first; this releases it on every return path.
+func (s *HTTPCacheServer) revalidateInBackground(url, etag, lastModified string) {
+	defer s.endRevalidation(url)
+
+	orig, notModified, err := s.fetchFromOrigin(context.Background(), url, etag, lastModified)
+	if err != nil {
+		s.lgr.Warn("Background revalidation failed", logger.F("url", url), logger.F("err", err))
+		return
+	}
+
+	if notModified {
+		ttl := orig.CacheControl.TTL(orig.Expires, originCacheTTL)
+		s.cache.RefreshValidated(url, ttl, orig.ETag, orig.LastModified, orig.CacheControl)
+		s.lgr.Debug("Background revalidation: 304, entry refreshed", logger.F("url", url))
+		return
+	}
+
+	s.storeOriginResponse(url, orig)
+	s.lgr.Debug("Background revalidation: content changed, entry replaced", logger.F("url", url))
+}
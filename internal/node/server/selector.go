@@ -0,0 +1,219 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ewmaAlpha weights how much an EWMA latency sample carries forward
+// history versus the new observation. Higher means smoother/slower to
+// react.
+const ewmaAlpha = 0.8
+
+// NodeSelector chooses a peer address to route a hotspot request to. It's
+// consulted by handleCacheRequest's hotspot-distribution path instead of
+// the inline rand.Intn selection it used to do.
+type NodeSelector interface {
+	// Select returns one of candidates to route r to. candidates is never
+	// empty.
+	Select(r *http.Request, candidates []string) (string, error)
+}
+
+// peerEntry is the bookkeeping PeerStats keeps for one peer address.
+type peerEntry struct {
+	inFlight    atomic.Int64
+	ewmaLatency atomic.Int64 // milliseconds
+}
+
+// PeerStats tracks in-flight request counts and an EWMA of observed
+// latency per peer address, so selectors like LeastRequestsSelector (and a
+// future least-latency policy) have real data to pick from instead of
+// picking blind.
+type PeerStats struct {
+	peers sync.Map // addr string -> *peerEntry
+}
+
+// NewPeerStats creates an empty PeerStats.
+func NewPeerStats() *PeerStats {
+	return &PeerStats{}
+}
+
+func (ps *PeerStats) entry(addr string) *peerEntry {
+	v, _ := ps.peers.LoadOrStore(addr, &peerEntry{})
+	return v.(*peerEntry)
+}
+
+// BeginRequest marks the start of a request to addr and returns a func to
+// call when it completes (typically via defer), which decrements the
+// in-flight count and folds the observed latency into addr's EWMA.
+func (ps *PeerStats) BeginRequest(addr string) func() {
+	e := ps.entry(addr)
+	e.inFlight.Add(1)
+	start := time.Now()
+	return func() {
+		e.inFlight.Add(-1)
+		sample := float64(time.Since(start).Milliseconds())
+		for {
+			old := e.ewmaLatency.Load()
+			next := int64(sample)
+			if old != 0 {
+				next = int64(ewmaAlpha*float64(old) + (1-ewmaAlpha)*sample)
+			}
+			if e.ewmaLatency.CompareAndSwap(old, next) {
+				break
+			}
+		}
+	}
+}
+
+// InFlight returns the number of requests currently in flight to addr.
+func (ps *PeerStats) InFlight(addr string) int64 {
+	v, ok := ps.peers.Load(addr)
+	if !ok {
+		return 0
+	}
+	return v.(*peerEntry).inFlight.Load()
+}
+
+// EWMALatencyMs returns the latest EWMA latency observed for addr, in
+// milliseconds, or 0 if no request to addr has completed yet.
+func (ps *PeerStats) EWMALatencyMs(addr string) int64 {
+	v, ok := ps.peers.Load(addr)
+	if !ok {
+		return 0
+	}
+	return v.(*peerEntry).ewmaLatency.Load()
+}
+
+// RandomSelector picks uniformly at random, matching the pre-NodeSelector
+// inline behavior.
+type RandomSelector struct{}
+
+func (RandomSelector) Select(r *http.Request, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("node selector: no candidates")
+	}
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+// RoundRobinSelector cycles through candidates via an atomic counter shared
+// across calls, so distribution stays even regardless of call concurrency.
+type RoundRobinSelector struct {
+	counter atomic.Uint64
+}
+
+func (s *RoundRobinSelector) Select(r *http.Request, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("node selector: no candidates")
+	}
+	i := s.counter.Add(1) - 1
+	return candidates[i%uint64(len(candidates))], nil
+}
+
+// LeastRequestsSelector routes to whichever candidate currently has the
+// fewest in-flight requests, per stats.
+type LeastRequestsSelector struct {
+	stats *PeerStats
+}
+
+// NewLeastRequestsSelector creates a LeastRequestsSelector reading from
+// stats (typically an HTTPCacheServer's peerStats, so it sees the same
+// in-flight counts BeginRequest/done updates around every proxied call).
+func NewLeastRequestsSelector(stats *PeerStats) *LeastRequestsSelector {
+	return &LeastRequestsSelector{stats: stats}
+}
+
+func (s *LeastRequestsSelector) Select(r *http.Request, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("node selector: no candidates")
+	}
+	best := candidates[0]
+	bestLoad := s.stats.InFlight(best)
+	for _, addr := range candidates[1:] {
+		if load := s.stats.InFlight(addr); load < bestLoad {
+			best, bestLoad = addr, load
+		}
+	}
+	return best, nil
+}
+
+// HeaderHashSelector consistently maps requests carrying the same value for
+// Header to the same candidate, giving session-stickiness across hotspot
+// replicas (e.g. keying on "X-Client-ID" or "X-Session"). Requests without
+// the header fall back to RandomSelector.
+type HeaderHashSelector struct {
+	Header string
+}
+
+func (s *HeaderHashSelector) Select(r *http.Request, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("node selector: no candidates")
+	}
+	key := r.Header.Get(s.Header)
+	if key == "" {
+		return RandomSelector{}.Select(r, candidates)
+	}
+
+	// Sort first so the same candidate set always hashes the same way
+	// regardless of map/slice iteration order upstream.
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return sorted[h.Sum32()%uint32(len(sorted))], nil
+}
+
+// WeightedSelector picks candidates proportionally to static weights. A
+// candidate matches a weight entry if its address has that entry's key as
+// a prefix (e.g. a node ID hex prefix, if the deployment tags addresses
+// that way, or the address itself for an exact match); candidates matching
+// no entry get DefaultWeight.
+type WeightedSelector struct {
+	Weights       map[string]int
+	DefaultWeight int
+}
+
+func (s *WeightedSelector) Select(r *http.Request, candidates []string) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("node selector: no candidates")
+	}
+
+	def := s.DefaultWeight
+	if def <= 0 {
+		def = 1
+	}
+
+	weights := make([]int, len(candidates))
+	total := 0
+	for i, addr := range candidates {
+		w := def
+		for prefix, configured := range s.Weights {
+			if strings.HasPrefix(addr, prefix) {
+				w = configured
+				break
+			}
+		}
+		weights[i] = w
+		total += w
+	}
+	if total <= 0 {
+		return RandomSelector{}.Select(r, candidates)
+	}
+
+	pick := rand.Intn(total)
+	for i, w := range weights {
+		if pick < w {
+			return candidates[i], nil
+		}
+		pick -= w
+	}
+	return candidates[len(candidates)-1], nil
+}
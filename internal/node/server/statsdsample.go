@@ -0,0 +1,116 @@
+package server
+
+import (
+	"KoordeDHT/internal/metrics"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultStatsdSampleInterval is how often statsdSampleLoop reads the
+// cache/hotspot/routing/health snapshots it feeds to the emitter. This is
+// independent of (and normally shorter than) StatsdEmitter's own flush
+// interval, which governs how often those accumulated samples actually go
+// out over UDP.
+const defaultStatsdSampleInterval = 5 * time.Second
+
+// defaultStatsdTopHotURLs bounds how many of the hottest URLs
+// statsdSampleLoop reports individual rate gauges for.
+const defaultStatsdTopHotURLs = 10
+
+// SetStatsdEmitter wires e as this server's statsd backend: cache hit/miss
+// counters, current hotspot count, the top-N hot URLs' decayed rates,
+// Koorde's de-Bruijn-hop-count histogram, and a node health gauge are all
+// sampled and pushed to it once Start's background loop begins (see
+// statsdSampleLoop). Passing nil (the default) disables statsd entirely,
+// so a server with no emitter configured carries no sampling cost.
+func (s *HTTPCacheServer) SetStatsdEmitter(e *metrics.StatsdEmitter) {
+	s.statsd = e
+}
+
+// handleStatsdSample reports the last batch of values statsdSampleLoop
+// pushed to the statsd emitter, for the interactive client's `stats`
+// command to print without needing a real statsd collector running. If
+// no emitter is configured, reports an empty sample rather than 404,
+// since "statsd disabled" isn't an error condition worth distinguishing
+// here.
+func (s *HTTPCacheServer) handleStatsdSample(w http.ResponseWriter, r *http.Request) {
+	sample := map[string]float64{}
+	if s.statsd != nil {
+		sample = s.statsd.LastSample()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": s.statsd != nil,
+		"sample":  sample,
+	})
+}
+
+// statsdSampleLoop periodically snapshots cache, hotspot, routing, and
+// health state into s.statsd's Counter/Gauge handles, until ctx is
+// cancelled. Registered once here rather than threading emitter handles
+// through cache/hotspot/dht code, since every value it needs is already
+// assembled on HTTPCacheServer.
+func (s *HTTPCacheServer) statsdSampleLoop(ctx context.Context) {
+	hits := s.statsd.RegisterCounter("koorde.cache.hits")
+	misses := s.statsd.RegisterCounter("koorde.cache.misses")
+	hotspotCount := s.statsd.RegisterGauge("koorde.hotspot.count")
+	healthGauge := s.statsd.RegisterGauge("koorde.node.healthy")
+	hopGauges := make(map[int]*metrics.Gauge)
+	hotURLGauges := make(map[string]*metrics.Gauge)
+
+	var lastHits, lastMisses int64
+
+	ticker := time.NewTicker(defaultStatsdSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cacheMetrics := s.cache.GetMetrics()
+		if delta := cacheMetrics.Hits - lastHits; delta > 0 {
+			hits.Add(float64(delta))
+		}
+		if delta := cacheMetrics.Misses - lastMisses; delta > 0 {
+			misses.Add(float64(delta))
+		}
+		lastHits, lastMisses = cacheMetrics.Hits, cacheMetrics.Misses
+
+		hotURLs := s.hotspotDetector.GetHotURLs()
+		hotspotCount.Set(float64(len(hotURLs)))
+		if len(hotURLs) > defaultStatsdTopHotURLs {
+			hotURLs = hotURLs[:defaultStatsdTopHotURLs]
+		}
+		for _, url := range hotURLs {
+			g, ok := hotURLGauges[url]
+			if !ok {
+				g = s.statsd.RegisterGauge(fmt.Sprintf("koorde.hotspot.rate.%s", url))
+				hotURLGauges[url] = g
+			}
+			avg, _, _ := s.hotspotDetector.GetStats(url)
+			g.Set(avg)
+		}
+
+		routingStats := s.node.RoutingMetrics()
+		for i, count := range routingStats.DeBruijnSuccessHopCounts {
+			g, ok := hopGauges[i]
+			if !ok {
+				g = s.statsd.RegisterGauge(fmt.Sprintf("koorde.routing.hop_count.%d", i))
+				hopGauges[i] = g
+			}
+			g.Set(float64(count))
+		}
+
+		if s.healthRegistry.RunAll(ctx, nil).Healthy {
+			healthGauge.Set(1)
+		} else {
+			healthGauge.Set(0)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"KoordeDHT/internal/node/dht"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const watchHeartbeatInterval = 10 * time.Second
+
+// handleDebugWatch streams incremental routing-table diffs — predecessor
+// changes and successor-/de-Bruijn-list slot changes — as they happen, so
+// dashboards and rebalancers get a push-based alternative to polling
+// /debug. Requires the DHTNode to implement dht.RoutingEventSource;
+// responds 501 Not Implemented otherwise.
+//
+// Query parameters:
+//
+//	from_seq=N  replay every retained event with seq > N before streaming
+//	            new ones (bounded history; see dht.RoutingEventBus)
+//	wait=false  return only the from_seq catch-up batch and close, instead
+//	            of the default behavior of streaming further events too
+//
+// The response is chunked application/x-ndjson by default, or
+// text/event-stream when the client sends "Accept: text/event-stream". A
+// heartbeat line is written every 10s to keep intermediating proxies from
+// closing an idle connection.
+func (s *HTTPCacheServer) handleDebugWatch(w http.ResponseWriter, r *http.Request) {
+	source, ok := s.node.(dht.RoutingEventSource)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusNotImplemented, ErrCodeProtocolUnsupported, "routing event watch not supported for this protocol", nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeJSONError(w, r, http.StatusInternalServerError, ErrCodeInternal, "streaming not supported by this response writer", nil)
+		return
+	}
+
+	var fromSeq uint64
+	if raw := r.URL.Query().Get("from_seq"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			s.writeJSONError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid 'from_seq' query parameter", err)
+			return
+		}
+		fromSeq = parsed
+	}
+	wait := r.URL.Query().Get("wait") != "false"
+
+	sse := false
+	for _, accept := range r.Header.Values("Accept") {
+		if accept == "text/event-stream" {
+			sse = true
+			break
+		}
+	}
+
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	writeEvent := func(e dht.RoutingEvent) error {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if sse {
+			_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.Seq, payload)
+		} else {
+			_, err = fmt.Fprintf(w, "%s\n", payload)
+		}
+		return err
+	}
+
+	for _, e := range source.Events().Since(fromSeq) {
+		if err := writeEvent(e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	if !wait {
+		return
+	}
+
+	ch := source.Events().Subscribe()
+	defer source.Events().Unsubscribe(ch)
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(e); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if sse {
+				fmt.Fprint(w, ": heartbeat\n\n")
+			} else {
+				fmt.Fprint(w, "{}\n")
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/dht"
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// eventsFakeNode additionally implements dht.RoutingEventSource, for
+// handleDebugWatch tests that need a node whose protocol supports it.
+type eventsFakeNode struct {
+	fakeNode
+	bus *dht.RoutingEventBus
+}
+
+func (f *eventsFakeNode) Events() *dht.RoutingEventBus { return f.bus }
+
+func newWatchTestServer(node dht.DHTNode) *HTTPCacheServer {
+	return &HTTPCacheServer{node: node}
+}
+
+func TestHandleDebugWatchNotImplemented(t *testing.T) {
+	s := newAuthTestServer(nil) // node doesn't implement dht.RoutingEventSource
+
+	r := httptest.NewRequest(http.MethodGet, "/debug/watch", nil)
+	w := httptest.NewRecorder()
+	s.handleDebugWatch(w, r)
+
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestHandleDebugWatchInvalidFromSeq(t *testing.T) {
+	node := &eventsFakeNode{
+		fakeNode: fakeNode{self: &domain.Node{ID: domain.ID{0x01}, Addr: "127.0.0.1:9000"}},
+		bus:      dht.NewRoutingEventBus(),
+	}
+	s := newWatchTestServer(node)
+
+	r := httptest.NewRequest(http.MethodGet, "/debug/watch?from_seq=not-a-number", nil)
+	w := httptest.NewRecorder()
+	s.handleDebugWatch(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleDebugWatchCatchUpWithoutWaiting(t *testing.T) {
+	bus := dht.NewRoutingEventBus()
+	n1 := &domain.Node{ID: domain.ID{0x01}, Addr: "127.0.0.1:9001"}
+	n2 := &domain.Node{ID: domain.ID{0x02}, Addr: "127.0.0.1:9002"}
+	n3 := &domain.Node{ID: domain.ID{0x03}, Addr: "127.0.0.1:9003"}
+	bus.Publish(dht.EventSuccessorChanged, 0, nil, n1) // seq 1
+	bus.Publish(dht.EventSuccessorChanged, 0, n1, n2)  // seq 2
+	bus.Publish(dht.EventSuccessorChanged, 0, n2, n3)  // seq 3
+
+	node := &eventsFakeNode{
+		fakeNode: fakeNode{self: &domain.Node{ID: domain.ID{0x00}, Addr: "127.0.0.1:9000"}},
+		bus:      bus,
+	}
+	s := newWatchTestServer(node)
+
+	// from_seq=1 with wait=false should replay seq 2 and 3, then close
+	// without blocking on further events.
+	r := httptest.NewRequest(http.MethodGet, "/debug/watch?from_seq=1&wait=false", nil)
+	w := httptest.NewRecorder()
+	s.handleDebugWatch(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var lines []string
+	sc := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for sc.Scan() {
+		if line := sc.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d ndjson lines, want 2 (seq 2 and 3 only): %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], `"seq":2`) {
+		t.Errorf("lines[0] = %q, want it to contain seq 2", lines[0])
+	}
+	if !strings.Contains(lines[1], `"seq":3`) {
+		t.Errorf("lines[1] = %q, want it to contain seq 3", lines[1])
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// wsGUID is the fixed RFC 6455 §1.3 magic string concatenated onto a
+// client's Sec-WebSocket-Key before hashing, to prove the server actually
+// understands the WebSocket handshake rather than just echoing the key.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsAcceptKey computes the Sec-WebSocket-Accept header value for clientKey
+// per RFC 6455 §1.3.
+func wsAcceptKey(clientKey string) string {
+	h := sha1.New()
+	io.WriteString(h, clientKey)
+	io.WriteString(h, wsGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// wsOpcode values this server uses: it writes text data frames and watches
+// for the client's close frame. handleCacheEvents is a one-way push feed, so no
+// fragmentation, ping, or binary support is implemented.
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+)
+
+// writeWSFrame writes a single unmasked, final (FIN-set) RFC 6455 frame.
+// Servers never mask frames they send (§5.1), unlike clients.
+func writeWSFrame(w io.Writer, opcode byte, payload []byte) error {
+	var header []byte
+	finAndOpcode := byte(0x80) | opcode
+
+	switch {
+	case len(payload) <= 125:
+		header = []byte{finAndOpcode, byte(len(payload))}
+	case len(payload) <= 0xFFFF:
+		header = make([]byte, 4)
+		header[0] = finAndOpcode
+		header[1] = 126
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header = make([]byte, 10)
+		header[0] = finAndOpcode
+		header[1] = 127
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// writeWSText writes payload as a single text-data frame.
+func writeWSText(w io.Writer, payload []byte) error {
+	return writeWSFrame(w, wsOpText, payload)
+}
+
+// discardWSFrames reads and discards client frames until the connection is
+// closed or a close frame arrives, so the hijacked connection's read side
+// doesn't sit un-drained for the lifetime of handleCacheEvents's push loop.
+// It never needs to inspect frame contents: this feed is one-way, so any
+// client frame (ping, close, or otherwise) just means "stop pushing".
+func discardWSFrames(r *bufio.Reader, done chan<- struct{}) {
+	defer close(done)
+	for {
+		first, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		opcode := first & 0x0F
+
+		second, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		masked := second&0x80 != 0
+		length := uint64(second & 0x7F)
+
+		switch length {
+		case 126:
+			buf := make([]byte, 2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			length = uint64(binary.BigEndian.Uint16(buf))
+		case 127:
+			buf := make([]byte, 8)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			length = binary.BigEndian.Uint64(buf)
+		}
+
+		if masked {
+			if _, err := io.CopyN(io.Discard, r, 4); err != nil {
+				return
+			}
+		}
+		if length > 0 {
+			if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+				return
+			}
+		}
+
+		if opcode == wsOpClose {
+			return
+		}
+	}
+}
+
+// isWebSocketUpgrade reports whether r is requesting a WebSocket upgrade
+// with the fields writeWSHandshake needs.
+func isWebSocketUpgrade(upgrade, connection, key, version string) bool {
+	if !strings.EqualFold(upgrade, "websocket") || key == "" || version == "" {
+		return false
+	}
+	for _, token := range strings.Split(connection, ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWSHandshake writes the 101 Switching Protocols response accepting
+// clientKey.
+func writeWSHandshake(w io.Writer, clientKey string) error {
+	_, err := fmt.Fprintf(w,
+		"HTTP/1.1 101 Switching Protocols\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Sec-WebSocket-Accept: %s\r\n\r\n",
+		wsAcceptKey(clientKey))
+	return err
+}
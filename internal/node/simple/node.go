@@ -15,13 +15,16 @@ import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
 	"KoordeDHT/internal/node/dht"
 	"KoordeDHT/internal/node/storage"
 	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -41,7 +44,33 @@ type Node struct {
 	self         *domain.Node   // This node's identity
 	clusterNodes []*domain.Node // All nodes in the cluster (sorted by address)
 	nodeIndex    int            // This node's index in the sorted cluster list
-}
+
+	checksumMismatches atomic.Uint64
+
+	// keyRedistributions counts AddNode/RemoveNode calls that changed
+	// cluster membership, surfaced via RoutingMetrics.KeyRedistributionCount.
+	// Every such change remaps nearly 100% of keys under hash(key) % N, so
+	// a simple count of membership edits is itself the meaningful signal
+	// here — there's no cheaper way to learn how many keys actually moved
+	// without re-hashing the whole keyspace.
+	keyRedistributions atomic.Uint64
+
+	// forwardCount, forwardFailures, and forwardDurationNs cover Put/Get/
+	// Delete calls this node forwarded to the node it believed responsible
+	// rather than serving locally, surfaced via RoutingMetrics.ForwardCount/
+	// ForwardFailureCount/AvgForwardLatencyMs.
+	forwardCount      atomic.Uint64
+	forwardFailures   atomic.Uint64
+	forwardDurationNs atomic.Int64
+}
+
+// defaultForwardHopBudget bounds how many times a single Put/Get/Delete can
+// be re-forwarded from node to node before giving up, so membership skew
+// between nodes (each believing a different peer is responsible) can't
+// become an infinite forwarding loop. Two hops covers the realistic case —
+// our view of the cluster is stale by exactly one membership edit — without
+// letting a persistently disagreeing cluster spin forever.
+const defaultForwardHopBudget = 2
 
 // New creates a new simple hash node.
 //
@@ -164,17 +193,31 @@ func (n *Node) Stop() {
 	n.lgr.Info("simple: node stopped")
 }
 
-// Put stores a resource. For simple hash, it stores locally if we're responsible.
+// Put stores a resource. For simple hash, it stores locally if we're
+// responsible, and otherwise forwards the request to the node that is.
 func (n *Node) Put(ctx context.Context, res domain.Resource) error {
+	res.Checksum, res.StrongDigest = domain.ChecksumResource(res.Value, true)
 	if n.isResponsible(res.Key) {
 		return n.StoreLocal(ctx, res)
 	}
-	// In a full implementation, we would forward to the responsible node
-	// For now, we just return an error (the HTTP layer handles forwarding)
-	return fmt.Errorf("simple: not responsible for key %s", res.Key.ToHexString(true))
+
+	fctx, responsible, err := n.beginForward(ctx, res.Key)
+	if err != nil {
+		return err
+	}
+	cli, err := n.cp.GetFromPool(responsible.Addr)
+	if err != nil {
+		n.endForward(0, err)
+		return err
+	}
+	start := time.Now()
+	_, err = client2.StoreRemote(fctx, cli, []domain.Resource{res})
+	n.endForward(time.Since(start), err)
+	return err
 }
 
-// Get retrieves a resource. For simple hash, it retrieves locally if we're responsible.
+// Get retrieves a resource. For simple hash, it retrieves locally if we're
+// responsible, and otherwise forwards the request to the node that is.
 func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error) {
 	if n.isResponsible(id) {
 		res, err := n.RetrieveLocal(id)
@@ -183,17 +226,86 @@ func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error)
 		}
 		return &res, nil
 	}
-	return nil, fmt.Errorf("simple: not responsible for key %s", id.ToHexString(true))
+
+	fctx, responsible, err := n.beginForward(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := n.cp.GetFromPool(responsible.Addr)
+	if err != nil {
+		n.endForward(0, err)
+		return nil, err
+	}
+	start := time.Now()
+	res, err := client2.RetrieveRemote(fctx, cli, n.Space(), id)
+	n.endForward(time.Since(start), err)
+	return res, err
 }
 
-// Delete removes a resource.
+// Delete removes a resource, forwarding to the responsible node if that
+// isn't this one.
 func (n *Node) Delete(ctx context.Context, id domain.ID) error {
 	if n.isResponsible(id) {
 		return n.RemoveLocal(id)
 	}
+
+	fctx, responsible, err := n.beginForward(ctx, id)
+	if err != nil {
+		return err
+	}
+	cli, err := n.cp.GetFromPool(responsible.Addr)
+	if err != nil {
+		n.endForward(0, err)
+		return err
+	}
+	start := time.Now()
+	err = client2.RemoveRemote(fctx, cli, id)
+	n.endForward(time.Since(start), err)
+	return err
+}
+
+// beginForward resolves the node responsible for id and returns a child
+// context carrying a decremented hop budget (see defaultForwardHopBudget),
+// erroring out instead if the budget is already exhausted.
+func (n *Node) beginForward(ctx context.Context, id domain.ID) (context.Context, *domain.Node, error) {
+	remaining, ok := ctxutil.HopsRemaining(ctx)
+	if !ok {
+		remaining = defaultForwardHopBudget
+	}
+	if remaining <= 0 {
+		return nil, nil, fmt.Errorf("simple: forward hop budget exhausted for key %s", id.ToHexString(true))
+	}
+	return ctxutil.WithHopBudget(ctx, remaining-1), n.getResponsibleNode(id), nil
+}
+
+// endForward records a completed forward attempt in the node's forwarding
+// counters, surfaced via RoutingMetrics.
+func (n *Node) endForward(d time.Duration, err error) {
+	n.forwardCount.Add(1)
+	n.forwardDurationNs.Add(d.Nanoseconds())
+	if err != nil {
+		n.forwardFailures.Add(1)
+	}
+}
+
+// PutStream stores the resource at id from r without buffering the whole
+// payload in memory. Unlike Put, it does not forward to the
+// responsible node — it either stores locally or returns an error.
+func (n *Node) PutStream(ctx context.Context, id domain.ID, meta dht.ResourceMeta, r io.Reader) error {
+	if n.isResponsible(id) {
+		return n.s.PutStream(id, meta, r)
+	}
 	return fmt.Errorf("simple: not responsible for key %s", id.ToHexString(true))
 }
 
+// GetStream retrieves the resource at id as a seekable stream.
+func (n *Node) GetStream(ctx context.Context, id domain.ID) (dht.ResourceReadSeekCloser, error) {
+	if n.isResponsible(id) {
+		return n.s.GetStream(id)
+	}
+	return nil, fmt.Errorf("simple: not responsible for key %s", id.ToHexString(true))
+}
+
 // LookUp finds the node responsible for the given ID using modulo hashing.
 func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
 	responsible := n.getResponsibleNode(id)
@@ -258,8 +370,9 @@ func (n *Node) Predecessor() *domain.Node {
 	return n.clusterNodes[predIdx]
 }
 
-// HandleLeave processes a leave notification (no-op for simple hash).
-func (n *Node) HandleLeave(leaveNode *domain.Node) error {
+// HandleLeave processes a leave notification (no-op for simple hash, since
+// membership here is static and managed via AddNode/RemoveNode instead).
+func (n *Node) HandleLeave(ctx context.Context, notice dht.LeaveNotification) error {
 	return nil
 }
 
@@ -290,15 +403,30 @@ func (n *Node) GetAllResourceStored() []domain.Resource {
 	return n.s.All()
 }
 
-// StoreLocal stores a resource locally.
+// StoreLocal stores a resource locally, verifying its integrity metadata first.
 func (n *Node) StoreLocal(ctx context.Context, res domain.Resource) error {
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("StoreLocal: integrity check failed", logger.F("key", res.Key.ToHexString(true)), logger.F("err", err))
+		return err
+	}
 	n.s.Put(res)
 	return nil
 }
 
-// RetrieveLocal retrieves a resource locally.
+// RetrieveLocal retrieves a resource locally, verifying its integrity
+// metadata before returning it.
 func (n *Node) RetrieveLocal(id domain.ID) (domain.Resource, error) {
-	return n.s.Get(id)
+	res, err := n.s.Get(id)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("RetrieveLocal: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return domain.Resource{}, err
+	}
+	return res, nil
 }
 
 // RemoveLocal removes a resource locally.
@@ -306,6 +434,21 @@ func (n *Node) RemoveLocal(id domain.ID) error {
 	return n.s.Delete(id)
 }
 
+// VerifyIntegrity re-checks the locally stored resource at id against its
+// integrity metadata, for use by background scrub passes.
+func (n *Node) VerifyIntegrity(id domain.ID) error {
+	res, err := n.s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := domain.VerifyResource(res); err != nil {
+		n.checksumMismatches.Add(1)
+		n.lgr.Warn("VerifyIntegrity: integrity check failed", logger.F("key", id.ToHexString(true)), logger.F("err", err))
+		return err
+	}
+	return nil
+}
+
 // CreateNewDHT initializes the node (no-op for simple hash - already initialized).
 func (n *Node) CreateNewDHT() {
 	n.lgr.Info("simple: CreateNewDHT called (cluster already initialized)")
@@ -319,8 +462,17 @@ func (n *Node) StartStabilizers(ctx context.Context, stabilizationInterval, deBr
 
 // RoutingMetrics returns routing statistics.
 func (n *Node) RoutingMetrics() dht.RoutingMetrics {
+	var avgForwardMs float64
+	if fc := n.forwardCount.Load(); fc > 0 {
+		avgForwardMs = float64(n.forwardDurationNs.Load()) / float64(fc) / float64(time.Millisecond)
+	}
 	return dht.RoutingMetrics{
-		Protocol: "simple",
+		Protocol:               "simple",
+		ChecksumMismatchCount:  n.checksumMismatches.Load(),
+		KeyRedistributionCount: n.keyRedistributions.Load(),
+		ForwardCount:           n.forwardCount.Load(),
+		ForwardFailureCount:    n.forwardFailures.Load(),
+		AvgForwardLatencyMs:    avgForwardMs,
 	}
 }
 
@@ -366,6 +518,8 @@ func (n *Node) RemoveNode(addr string) error {
 		}
 	}
 
+	n.keyRedistributions.Add(1)
+
 	n.lgr.Info("simple: node removed from cluster",
 		logger.F("removed_addr", addr),
 		logger.F("new_cluster_size", len(n.clusterNodes)),
@@ -374,6 +528,78 @@ func (n *Node) RemoveNode(addr string) error {
 	return nil
 }
 
+// Scan walks the range (start, end] using only this node's local storage:
+// simple hash has no ring ordering to hop across (ownership is hash(key) %
+// N, not range-based), so unlike chord.Node.Scan it cannot visit other
+// nodes' resources. Callers that need a cluster-wide scan must issue Scan
+// against every node and merge the results themselves.
+func (n *Node) Scan(ctx context.Context, start, end domain.ID, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	low := start
+	if opts.Cursor != "" {
+		cursor, err := dht.DecodeScanCursor(n.space, opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		low = cursor.LastID
+	}
+
+	var matches []domain.Resource
+	for _, res := range n.GetAllResourceStored() {
+		if res.Key.Between(low, end) {
+			matches = append(matches, res)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Key.Cmp(matches[j].Key) < 0 })
+
+	return &localScanIterator{self: n.self, matches: matches, start: low}, nil
+}
+
+// PrefixScan derives a (start, end] range from prefix/prefixBits via
+// dht.PrefixRange and delegates to Scan.
+func (n *Node) PrefixScan(ctx context.Context, prefix []byte, prefixBits int, opts dht.ScanOptions) (dht.ResourceIterator, error) {
+	start, end, err := dht.PrefixRange(n.space, prefix, prefixBits)
+	if err != nil {
+		return nil, err
+	}
+	return n.Scan(ctx, start, end, opts)
+}
+
+// localScanIterator implements dht.ResourceIterator over a pre-materialized,
+// already-sorted slice of locally stored resources.
+type localScanIterator struct {
+	self    *domain.Node
+	matches []domain.Resource
+	start   domain.ID // lower bound the scan began from, for Cursor before Next
+	pos     int
+	cur     domain.Resource
+}
+
+func (it *localScanIterator) Next(ctx context.Context) bool {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return false
+	}
+	if it.pos >= len(it.matches) {
+		return false
+	}
+	it.cur = it.matches[it.pos]
+	it.pos++
+	return true
+}
+
+func (it *localScanIterator) Resource() domain.Resource { return it.cur }
+
+func (it *localScanIterator) Cursor() string {
+	last := it.cur.Key
+	if it.pos == 0 {
+		last = it.start
+	}
+	return dht.EncodeScanCursor(dht.ScanCursor{LastID: last, ServedBy: it.self.Addr})
+}
+
+func (it *localScanIterator) Err() error { return nil }
+
+func (it *localScanIterator) Close() error { return nil }
+
 // AddNode adds a node to the cluster membership.
 // This is used to update membership when a new node joins.
 // After addition, keys will be remapped using hash(key) % (N+1).
@@ -411,6 +637,8 @@ func (n *Node) AddNode(addr string) error {
 		}
 	}
 
+	n.keyRedistributions.Add(1)
+
 	n.lgr.Info("simple: node added to cluster",
 		logger.F("added_addr", addr),
 		logger.F("new_cluster_size", len(n.clusterNodes)),
@@ -0,0 +1,102 @@
+// Package storage is the local storage backend for resources a node owns
+// or holds as a replica. Storage keeps small resources (those stored via
+// Put) entirely in memory; PutStream/GetStream back large objects with
+// content-addressed files on disk instead (see stream.go).
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned when a resource is not present in local storage.
+var ErrNotFound = errors.New("storage: resource not found")
+
+// Option configures a Storage at construction time.
+type Option func(*Storage)
+
+// WithStreamDir sets the directory PutStream/GetStream use for
+// content-addressed files. It must be called for those methods to work;
+// NewMemoryStorage alone only supports the in-memory Put/Get/Delete/All
+// path.
+func WithStreamDir(dir string) Option {
+	return func(s *Storage) { s.streamDir = dir }
+}
+
+// WithChunkSize overrides the chunk size PutStream uses when digesting and
+// writing a stream. The default is defaultChunkSize.
+func WithChunkSize(n int64) Option {
+	return func(s *Storage) { s.chunkSize = n }
+}
+
+// Storage is the local storage backend for a single node.
+type Storage struct {
+	lgr logger.Logger
+
+	mu   sync.RWMutex
+	data map[string]domain.Resource
+
+	streamDir string
+	chunkSize int64
+	streamMu  sync.RWMutex
+	streamed  map[string]*streamedEntry
+}
+
+// NewMemoryStorage creates an empty Storage. Pass WithStreamDir to enable
+// PutStream/GetStream.
+func NewMemoryStorage(lgr logger.Logger, opts ...Option) *Storage {
+	s := &Storage{
+		lgr:      lgr,
+		data:     make(map[string]domain.Resource),
+		streamed: make(map[string]*streamedEntry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Put stores res, overwriting any existing resource with the same key.
+func (s *Storage) Put(res domain.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[res.Key.ToHexString(false)] = res
+}
+
+// Get returns the resource stored at id, or ErrNotFound if none exists.
+func (s *Storage) Get(id domain.ID) (domain.Resource, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res, ok := s.data[id.ToHexString(false)]
+	if !ok {
+		return domain.Resource{}, ErrNotFound
+	}
+	return res, nil
+}
+
+// Delete removes the resource stored at id, or returns ErrNotFound if none
+// exists.
+func (s *Storage) Delete(id domain.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := id.ToHexString(false)
+	if _, ok := s.data[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.data, key)
+	return nil
+}
+
+// All returns every resource currently stored locally, in no particular
+// order.
+func (s *Storage) All() []domain.Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]domain.Resource, 0, len(s.data))
+	for _, res := range s.data {
+		out = append(out, res)
+	}
+	return out
+}
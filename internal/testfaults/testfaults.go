@@ -0,0 +1,140 @@
+// Package testfaults provides a shared fault-injection primitive for
+// integration tests: a Controller that decides, for a given (src, dst)
+// pair of peer addresses, whether a call between them should be dropped
+// and how long it should be delayed first. It has no dependency on any
+// particular protocol or transport, so it can back fault injection for
+// Chord (see chord/chaostest), Koorde, or anything else that dials peers
+// by address.
+package testfaults
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// FaultPlan describes the fault behavior to inject between one (src, dst)
+// pair of peers.
+type FaultPlan struct {
+	// DropRate is the probability, in [0,1], that a call is dropped
+	// (treated as a dial/RPC failure) instead of proceeding.
+	DropRate float64
+
+	// LatencyDist samples an artificial delay to apply before a call
+	// proceeds or is dropped. Nil means no added delay.
+	LatencyDist func() time.Duration
+
+	// PartitionSet unconditionally drops every call on this pair,
+	// regardless of DropRate, simulating a severed link rather than a
+	// lossy one.
+	PartitionSet bool
+
+	// ExpireAfter, if non-zero, stops applying this plan once it has been
+	// registered for longer than ExpireAfter, so a test can schedule a
+	// fault to heal itself instead of clearing it manually.
+	ExpireAfter time.Duration
+
+	registeredAt time.Time
+}
+
+// pairKey identifies one directed edge a FaultPlan can be registered for.
+type pairKey struct {
+	src, dst string
+}
+
+// Controller holds a FaultPlan per (src, dst) pair. A FaultyDialer (see
+// chord/chaostest.FaultyDialer) consults it on every dial. Controller is
+// safe for concurrent use.
+type Controller struct {
+	mu    sync.Mutex
+	plans map[pairKey]*FaultPlan
+}
+
+// NewController returns a Controller with no fault plans registered: every
+// pair behaves as an unfaulted network until Set, Partition, or Isolate is
+// called.
+func NewController() *Controller {
+	return &Controller{plans: make(map[pairKey]*FaultPlan)}
+}
+
+// Set installs plan for calls from src to dst, replacing any existing plan
+// for that directed pair. It does not affect calls from dst to src; call
+// Set twice (or use Partition/Isolate) for a symmetric fault.
+func (c *Controller) Set(src, dst string, plan FaultPlan) {
+	plan.registeredAt = time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plans[pairKey{src, dst}] = &plan
+}
+
+// Clear removes any fault plan for calls from src to dst, restoring normal
+// delivery on that directed pair.
+func (c *Controller) Clear(src, dst string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.plans, pairKey{src, dst})
+}
+
+// ClearAll removes every registered fault plan.
+func (c *Controller) ClearAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plans = make(map[pairKey]*FaultPlan)
+}
+
+// Partition installs an unconditional drop between every distinct pair of
+// addresses in members, in both directions, cutting the members off from
+// each other. Calls between a member and an address outside members are
+// not affected.
+func (c *Controller) Partition(members []string) {
+	for _, src := range members {
+		for _, dst := range members {
+			if src == dst {
+				continue
+			}
+			c.Set(src, dst, FaultPlan{PartitionSet: true})
+		}
+	}
+}
+
+// Isolate cuts bidirectional traffic between hub and every address in
+// others, without affecting traffic among the addresses in others
+// themselves — e.g. partitioning one node from its successor list without
+// also partitioning those successors from each other.
+func (c *Controller) Isolate(hub string, others []string) {
+	for _, other := range others {
+		c.Set(hub, other, FaultPlan{PartitionSet: true})
+		c.Set(other, hub, FaultPlan{PartitionSet: true})
+	}
+}
+
+// Evaluate decides whether a call from src to dst should be dropped and
+// how long to delay it first, consulting the plan registered for (src,
+// dst), if any. A plan that has outlived its ExpireAfter is evicted and
+// treated as absent.
+func (c *Controller) Evaluate(src, dst string) (drop bool, delay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := pairKey{src, dst}
+	plan, ok := c.plans[key]
+	if !ok {
+		return false, 0
+	}
+	if plan.ExpireAfter > 0 && time.Since(plan.registeredAt) > plan.ExpireAfter {
+		delete(c.plans, key)
+		return false, 0
+	}
+
+	if plan.LatencyDist != nil {
+		delay = plan.LatencyDist()
+	}
+	if plan.PartitionSet {
+		return true, delay
+	}
+	if plan.DropRate > 0 && rand.Float64() < plan.DropRate {
+		return true, delay
+	}
+	return false, delay
+}
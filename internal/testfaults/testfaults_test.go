@@ -0,0 +1,81 @@
+package testfaults
+
+import (
+	"testing"
+	"time"
+)
+
+func TestControllerUnplannedPairIsUnfaulted(t *testing.T) {
+	c := NewController()
+	drop, delay := c.Evaluate("a", "b")
+	if drop || delay != 0 {
+		t.Fatalf("unplanned pair should be unfaulted, got drop=%v delay=%v", drop, delay)
+	}
+}
+
+func TestControllerPartitionSetDropsBothDirections(t *testing.T) {
+	c := NewController()
+	c.Partition([]string{"a", "b"})
+
+	if drop, _ := c.Evaluate("a", "b"); !drop {
+		t.Fatal("expected a->b to be dropped after Partition")
+	}
+	if drop, _ := c.Evaluate("b", "a"); !drop {
+		t.Fatal("expected b->a to be dropped after Partition")
+	}
+}
+
+func TestControllerIsolateDoesNotAffectOthersAmongThemselves(t *testing.T) {
+	c := NewController()
+	c.Isolate("hub", []string{"a", "b"})
+
+	if drop, _ := c.Evaluate("hub", "a"); !drop {
+		t.Fatal("expected hub->a to be dropped after Isolate")
+	}
+	if drop, _ := c.Evaluate("a", "b"); drop {
+		t.Fatal("Isolate should not affect traffic between the isolated peers themselves")
+	}
+}
+
+func TestControllerFullDropRate(t *testing.T) {
+	c := NewController()
+	c.Set("a", "b", FaultPlan{DropRate: 1})
+
+	if drop, _ := c.Evaluate("a", "b"); !drop {
+		t.Fatal("expected DropRate 1 to always drop")
+	}
+}
+
+func TestControllerLatencyDist(t *testing.T) {
+	c := NewController()
+	c.Set("a", "b", FaultPlan{LatencyDist: func() time.Duration { return 50 * time.Millisecond }})
+
+	drop, delay := c.Evaluate("a", "b")
+	if drop {
+		t.Fatal("a plan with only LatencyDist should not drop")
+	}
+	if delay != 50*time.Millisecond {
+		t.Fatalf("expected delay 50ms, got %v", delay)
+	}
+}
+
+func TestControllerExpireAfterEvicts(t *testing.T) {
+	c := NewController()
+	c.Set("a", "b", FaultPlan{PartitionSet: true, ExpireAfter: time.Millisecond})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if drop, _ := c.Evaluate("a", "b"); drop {
+		t.Fatal("expected the plan to have expired and no longer drop")
+	}
+}
+
+func TestControllerClear(t *testing.T) {
+	c := NewController()
+	c.Set("a", "b", FaultPlan{PartitionSet: true})
+	c.Clear("a", "b")
+
+	if drop, _ := c.Evaluate("a", "b"); drop {
+		t.Fatal("expected Clear to restore normal delivery")
+	}
+}